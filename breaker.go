@@ -0,0 +1,235 @@
+package dana
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+const (
+	defaultCircuitFailureThreshold       = 5
+	defaultCircuitCooldownSeconds  int64 = 30
+)
+
+const (
+	circuitSuccess = iota
+	circuitFailure
+	circuitNeutral
+)
+
+var ErrCircuitOpen = errors.New("dana circuit breaker is open")
+
+type CircuitState string
+
+type CircuitStateHook func(endpoint string, from, to CircuitState)
+
+type CircuitBreakerConfig struct {
+	Enable           bool  `json:"enable"`
+	FailureThreshold int   `json:"failure_threshold"`
+	CooldownSeconds  int64 `json:"cooldown_seconds"`
+}
+
+type circuit struct {
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func (c *Client) SetCircuitStateHook(hook CircuitStateHook) {
+	c.circuitMutex.Lock()
+	defer c.circuitMutex.Unlock()
+
+	c.circuitHook = hook
+}
+
+func (c *Client) ClearCircuitStateHook() {
+	c.SetCircuitStateHook(nil)
+}
+
+func (c *Client) WithCircuitStateHook(hook CircuitStateHook) *Client {
+	c.SetCircuitStateHook(hook)
+
+	return c
+}
+
+func (c *Client) CircuitState(endpoint string) CircuitState {
+	c.circuitMutex.Lock()
+	defer c.circuitMutex.Unlock()
+
+	if cb, ok := c.circuits[endpoint]; ok {
+		return cb.state
+	}
+
+	return CircuitClosed
+}
+
+func (c *Client) CircuitStates() map[string]CircuitState {
+	c.circuitMutex.Lock()
+	defer c.circuitMutex.Unlock()
+
+	states := make(map[string]CircuitState, len(c.circuits))
+	for endpoint, cb := range c.circuits {
+		states[endpoint] = cb.state
+	}
+
+	return states
+}
+
+func (c *Client) ResetCircuits() {
+	c.circuitMutex.Lock()
+	defer c.circuitMutex.Unlock()
+
+	c.circuits = nil
+}
+
+func (c *Client) circuitBreakerMiddleware(config CircuitBreakerConfig) Middleware {
+	threshold := config.FailureThreshold
+	if threshold <= 0 {
+		threshold = defaultCircuitFailureThreshold
+	}
+
+	cooldownSeconds := config.CooldownSeconds
+	if cooldownSeconds <= 0 {
+		cooldownSeconds = defaultCircuitCooldownSeconds
+	}
+
+	cooldown := time.Duration(cooldownSeconds) * time.Second
+
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			if req.dryRun {
+				return next(ctx, req)
+			}
+
+			if !c.allowCircuit(ctx, req.Endpoint, cooldown) {
+				return nil, fmt.Errorf("%w: %s", ErrCircuitOpen, req.Endpoint)
+			}
+
+			resp, err := next(ctx, req)
+
+			c.recordCircuit(ctx, req.Endpoint, circuitOutcome(resp, err), threshold)
+
+			return resp, err
+		}
+	}
+}
+
+func (c *Client) allowCircuit(ctx context.Context, endpoint string, cooldown time.Duration) bool {
+	c.circuitMutex.Lock()
+
+	cb := c.getCircuit(endpoint)
+	from := cb.state
+	allowed := true
+
+	switch cb.state {
+	case CircuitOpen:
+		if time.Since(cb.openedAt) < cooldown {
+			allowed = false
+		} else {
+			cb.state = CircuitHalfOpen
+			cb.probing = true
+		}
+	case CircuitHalfOpen:
+		if cb.probing {
+			allowed = false
+		} else {
+			cb.probing = true
+		}
+	}
+
+	to := cb.state
+	hook := c.circuitHook
+
+	c.circuitMutex.Unlock()
+
+	c.notifyCircuit(ctx, hook, endpoint, from, to)
+
+	return allowed
+}
+
+func (c *Client) recordCircuit(ctx context.Context, endpoint string, outcome int, threshold int) {
+	c.circuitMutex.Lock()
+
+	cb := c.getCircuit(endpoint)
+	from := cb.state
+	cb.probing = false
+
+	switch outcome {
+	case circuitFailure:
+		cb.failures++
+
+		if cb.state == CircuitHalfOpen || cb.failures >= threshold {
+			cb.state = CircuitOpen
+			cb.openedAt = time.Now()
+		}
+	case circuitSuccess:
+		cb.failures = 0
+		cb.state = CircuitClosed
+	}
+
+	to := cb.state
+	hook := c.circuitHook
+
+	c.circuitMutex.Unlock()
+
+	c.notifyCircuit(ctx, hook, endpoint, from, to)
+}
+
+func (c *Client) getCircuit(endpoint string) *circuit {
+	if c.circuits == nil {
+		c.circuits = map[string]*circuit{}
+	}
+
+	cb, ok := c.circuits[endpoint]
+	if !ok {
+		cb = &circuit{state: CircuitClosed}
+		c.circuits[endpoint] = cb
+	}
+
+	return cb
+}
+
+func (c *Client) notifyCircuit(ctx context.Context, hook CircuitStateHook, endpoint string, from, to CircuitState) {
+	if from == to {
+		return
+	}
+
+	level := slog.LevelWarn
+	if to == CircuitClosed {
+		level = slog.LevelInfo
+	}
+
+	c.log(ctx, level, "dana circuit breaker state changed",
+		slog.String("endpoint", endpoint),
+		slog.String("from", string(from)),
+		slog.String("to", string(to)),
+	)
+
+	if hook != nil {
+		hook(endpoint, from, to)
+	}
+}
+
+func circuitOutcome(resp *Response, err error) int {
+	if resp != nil {
+		if resp.StatusCode >= 500 {
+			return circuitFailure
+		}
+
+		return circuitSuccess
+	}
+
+	if err == nil {
+		return circuitSuccess
+	}
+
+	var signErr signatureError
+	if errors.Is(err, context.Canceled) || errors.Is(err, ErrRateLimited) || errors.As(err, &signErr) || isDryRun(err) {
+		return circuitNeutral
+	}
+
+	return circuitFailure
+}
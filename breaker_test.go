@@ -0,0 +1,117 @@
+package dana
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type circuitStep struct {
+	outcome  string
+	wantErr  error
+	wantOpen bool
+	want     CircuitState
+}
+
+func TestCircuitBreakerTransitions(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps []circuitStep
+	}{
+		{
+			name: "closed to open to half open to closed",
+			steps: []circuitStep{
+				{outcome: "500", want: CircuitClosed},
+				{outcome: "500", want: CircuitOpen},
+				{outcome: "200", wantOpen: true, want: CircuitOpen},
+				{outcome: "cooldown", want: CircuitOpen},
+				{outcome: "200", want: CircuitClosed},
+			},
+		},
+		{
+			name: "open to half open to open",
+			steps: []circuitStep{
+				{outcome: "500", want: CircuitClosed},
+				{outcome: "500", want: CircuitOpen},
+				{outcome: "cooldown", want: CircuitOpen},
+				{outcome: "timeout", want: CircuitOpen},
+				{outcome: "200", wantOpen: true, want: CircuitOpen},
+			},
+		},
+		{
+			name: "success resets failures",
+			steps: []circuitStep{
+				{outcome: "500", want: CircuitClosed},
+				{outcome: "200", want: CircuitClosed},
+				{outcome: "500", want: CircuitClosed},
+			},
+		},
+		{
+			name: "neutral outcomes keep state and failures",
+			steps: []circuitStep{
+				{outcome: "500", want: CircuitClosed},
+				{outcome: "canceled", want: CircuitClosed},
+				{outcome: "rate limited", want: CircuitClosed},
+				{outcome: "signature", want: CircuitClosed},
+				{outcome: "500", want: CircuitOpen},
+			},
+		},
+		{
+			name: "canceled probe does not close the circuit",
+			steps: []circuitStep{
+				{outcome: "500", want: CircuitClosed},
+				{outcome: "500", want: CircuitOpen},
+				{outcome: "cooldown", want: CircuitOpen},
+				{outcome: "canceled", want: CircuitHalfOpen},
+				{outcome: "500", want: CircuitOpen},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(Config{})
+			handler := c.circuitBreakerMiddleware(CircuitBreakerConfig{Enable: true, FailureThreshold: 2, CooldownSeconds: 60})(scriptedHandler)
+
+			for i, step := range tt.steps {
+				if step.outcome == "cooldown" {
+					c.circuitMutex.Lock()
+					c.circuits["QueryPayment"].openedAt = time.Now().Add(-time.Hour)
+					c.circuitMutex.Unlock()
+
+					continue
+				}
+
+				_, err := handler(context.WithValue(context.Background(), circuitStepKey{}, step.outcome), &Request{Endpoint: "QueryPayment"})
+				if step.wantOpen != errors.Is(err, ErrCircuitOpen) {
+					t.Fatalf("step %d: got error %v, want circuit open %t", i, err, step.wantOpen)
+				}
+
+				if got := c.CircuitState("QueryPayment"); got != step.want {
+					t.Fatalf("step %d: got state %s, want %s", i, got, step.want)
+				}
+			}
+		})
+	}
+}
+
+type circuitStepKey struct{}
+
+func scriptedHandler(ctx context.Context, _ *Request) (*Response, error) {
+	switch ctx.Value(circuitStepKey{}) {
+	case "500":
+		return &Response{StatusCode: http.StatusInternalServerError}, errors.New("internal server error")
+	case "timeout":
+		return nil, context.DeadlineExceeded
+	case "canceled":
+		return nil, context.Canceled
+	case "rate limited":
+		return nil, ErrRateLimited
+	case "signature":
+		return nil, signatureError{err: errors.New("invalid key")}
+	default:
+		return &Response{StatusCode: http.StatusOK}, nil
+	}
+}
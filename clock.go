@@ -0,0 +1,81 @@
+package dana
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+type FixedClock struct {
+	Time time.Time
+}
+
+func (c FixedClock) Now() time.Time {
+	return c.Time
+}
+
+var fallbackLocation = time.FixedZone("WIB", 7*60*60)
+
+var defaultLocation = sync.OnceValue(func() *time.Location {
+	loc, err := time.LoadLocation(defaultTimezone)
+	if err != nil {
+		return fallbackLocation
+	}
+
+	return loc
+})
+
+func defaultNow() time.Time {
+	return time.Now().In(defaultLocation())
+}
+
+func (c *Client) getClock() Clock {
+	if c.clock != nil {
+		return c.clock
+	}
+
+	return systemClock{}
+}
+
+func (c *Client) getLocation() *time.Location {
+	currentTimezone := defaultTimezone
+	configTimezone := strings.TrimSpace(c.Config.Timezone)
+
+	if len(configTimezone) > 0 {
+		currentTimezone = configTimezone
+	}
+
+	c.locationMutex.Lock()
+	defer c.locationMutex.Unlock()
+
+	if c.location != nil && c.locationName == currentTimezone {
+		return c.location
+	}
+
+	loc, err := time.LoadLocation(currentTimezone)
+	if err != nil {
+		c.log(context.Background(), slog.LevelWarn, "error when load timezone, falling back to +07:00",
+			slog.String("timezone", currentTimezone),
+			errorAttr(err),
+		)
+
+		loc = fallbackLocation
+	}
+
+	c.location = loc
+	c.locationName = currentTimezone
+
+	return loc
+}
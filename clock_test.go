@@ -0,0 +1,73 @@
+package dana
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFixedClockTimestamps(t *testing.T) {
+	clock := FixedClock{Time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	tests := []struct {
+		timezone string
+		want     string
+	}{
+		{timezone: "", want: "2024-01-01T07:00:00+07:00"},
+		{timezone: "UTC", want: "2024-01-01T00:00:00+00:00"},
+		{timezone: "Asia/Makassar", want: "2024-01-01T08:00:00+08:00"},
+		{timezone: "Mars/Olympus_Mons", want: "2024-01-01T07:00:00+07:00"},
+	}
+
+	for _, tt := range tests {
+		c := New(Config{Timezone: tt.timezone}).WithClock(clock)
+
+		if got := c.getTimestamp(); got != tt.want {
+			t.Fatalf("timezone %q: got %s, want %s", tt.timezone, got, tt.want)
+		}
+	}
+
+	expireTime := int64(15)
+	if got, want := New(Config{}).WithClock(clock).getExpireTime(&expireTime), "2024-01-01T07:15:00+07:00"; got != want {
+		t.Fatalf("got expire time %s, want %s", got, want)
+	}
+}
+
+func TestFixedClockMakesSignaturesDeterministic(t *testing.T) {
+	var headers []http.Header
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = append(headers, r.Header.Clone())
+
+		_ = json.NewEncoder(w).Encode(map[string]string{"responseCode": "2005500"})
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL).
+		WithClock(FixedClock{Time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}).
+		WithB2BAccessToken(&AccessToken{AccessToken: "b2b-token", TokenType: "Bearer"})
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.WithRequestId("req-1").QueryPayment("ref-1"); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := c.WithRequestId("req-2").QuickPay("IDR", "10000.00", "ref-2", "51051000100000000001", "order", nil, nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		first, second := headers[i], headers[i+2]
+
+		if first.Get("X-TIMESTAMP") != "2024-01-01T07:00:00+07:00" {
+			t.Fatalf("got timestamp %s", first.Get("X-TIMESTAMP"))
+		}
+
+		if first.Get("X-SIGNATURE") != second.Get("X-SIGNATURE") {
+			t.Fatalf("request %d was signed differently on repeat", i)
+		}
+	}
+}
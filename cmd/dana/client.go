@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/vannleonheart/dana-api-go"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+type clientOptions struct {
+	configFile *string
+	envPrefix  *string
+	output     *string
+	dryRun     *bool
+}
+
+func addClientFlags(fs *flag.FlagSet) *clientOptions {
+	return &clientOptions{
+		configFile: fs.String("config", "", "config file, json or yaml; environment variables are used when empty"),
+		envPrefix:  fs.String("env-prefix", "DANA", "environment variable prefix"),
+		output:     fs.String("output", "json", "output format: json or table"),
+		dryRun:     fs.Bool("dry-run", false, "print the signed request instead of sending it"),
+	}
+}
+
+func (o *clientOptions) client() (*dana.Client, error) {
+	var cfg *dana.Config
+	var err error
+
+	if len(*o.configFile) > 0 {
+		cfg, err = dana.LoadConfigFromFile(*o.configFile)
+	} else {
+		cfg, err = dana.LoadConfigFromEnv(*o.envPrefix)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return dana.New(*cfg).WithDryRun(*o.dryRun), nil
+}
+
+func (o *clientOptions) print(result interface{}, err error) error {
+	if prepared, ok := dana.GetPreparedRequest(err); ok {
+		return printPreparedRequest(os.Stdout, *o.output, prepared)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return printResult(os.Stdout, *o.output, result)
+}
+
+func printPreparedRequest(w io.Writer, format string, prepared *dana.PreparedRequest) error {
+	if strings.ToLower(format) == "json" {
+		body := json.RawMessage(prepared.Body)
+		if !json.Valid(prepared.Body) {
+			by, err := json.Marshal(string(prepared.Body))
+			if err != nil {
+				return err
+			}
+
+			body = by
+		}
+
+		return printResult(w, format, map[string]interface{}{
+			"endpoint":     prepared.Endpoint,
+			"method":       prepared.Method,
+			"url":          prepared.Url,
+			"headers":      prepared.Headers,
+			"stringToSign": prepared.StringToSign,
+			"body":         body,
+		})
+	}
+
+	headers := make([]string, 0, len(prepared.Headers))
+	for name := range prepared.Headers {
+		headers = append(headers, name)
+	}
+
+	sort.Strings(headers)
+
+	_, _ = fmt.Fprintf(w, "%s %s\n", prepared.Method, prepared.Url)
+	for _, name := range headers {
+		_, _ = fmt.Fprintf(w, "%s: %s\n", name, prepared.Headers[name])
+	}
+
+	indented := &bytes.Buffer{}
+	if json.Indent(indented, prepared.Body, "", "  ") != nil {
+		indented = bytes.NewBuffer(prepared.Body)
+	}
+
+	_, _ = fmt.Fprintf(w, "\nstring to sign: %s\n\n%s\n", prepared.StringToSign, indented.String())
+
+	return nil
+}
+
+func printResult(w io.Writer, format string, result interface{}) error {
+	switch strings.ToLower(format) {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+
+		return encoder.Encode(result)
+	case "table":
+		by, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+
+		var raw interface{}
+		if err = json.Unmarshal(by, &raw); err != nil {
+			return err
+		}
+
+		rows := map[string]string{}
+		flatten(rows, "", raw)
+
+		keys := make([]string, 0, len(rows))
+		for k := range rows {
+			keys = append(keys, k)
+		}
+
+		sort.Strings(keys)
+
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		for _, k := range keys {
+			_, _ = fmt.Fprintf(tw, "%s\t%s\n", k, rows[k])
+		}
+
+		return tw.Flush()
+	default:
+		return fmt.Errorf("unknown output format %s", format)
+	}
+}
+
+func flatten(rows map[string]string, prefix string, value interface{}) {
+	join := func(key string) string {
+		if len(prefix) == 0 {
+			return key
+		}
+
+		return fmt.Sprintf("%s.%s", prefix, key)
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, item := range v {
+			flatten(rows, join(k), item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			flatten(rows, join(fmt.Sprintf("%d", i)), item)
+		}
+	case nil:
+		if len(prefix) > 0 {
+			rows[prefix] = ""
+		}
+	default:
+		rows[prefix] = fmt.Sprintf("%v", v)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"github.com/vannleonheart/dana-api-go"
+	"os"
+	"strings"
+)
+
+func runKeygen(args []string) error {
+	fs := newFlagSet("keygen")
+	bits := fs.Int("bits", 2048, "key size in bits")
+	format := fs.String("format", "pkcs8", "private key format: pkcs1 or pkcs8")
+	usePem := fs.Bool("pem", false, "print keys PEM-armored instead of bare base64 DER")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	keyPair, err := dana.GenerateKeyPair(*bits)
+	if err != nil {
+		return err
+	}
+
+	var privateKey, publicKey string
+
+	switch strings.ToLower(*format) {
+	case "pkcs1":
+		if *usePem {
+			privateKey = keyPair.PrivateKeyPKCS1PEM()
+		} else {
+			privateKey = keyPair.PrivateKeyPKCS1()
+		}
+	case "pkcs8":
+		if *usePem {
+			privateKey, err = keyPair.PrivateKeyPKCS8PEM()
+		} else {
+			privateKey, err = keyPair.PrivateKeyPKCS8()
+		}
+	default:
+		return fmt.Errorf("unknown key format %s", *format)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if *usePem {
+		publicKey, err = keyPair.PublicKeyPEM()
+	} else {
+		publicKey, err = keyPair.PublicKey()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("private_key: %s\n", strings.TrimSpace(privateKey))
+	fmt.Printf("public_key: %s\n", strings.TrimSpace(publicKey))
+
+	return nil
+}
+
+func runPubkey(args []string) error {
+	fs := newFlagSet("pubkey")
+	keyFile := fs.String("key", "", "private key file, PEM or base64 DER")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if len(*keyFile) == 0 {
+		return fmt.Errorf("-key is required")
+	}
+
+	data, err := os.ReadFile(*keyFile)
+	if err != nil {
+		return err
+	}
+
+	publicKey, err := dana.DerivePublicKey(string(data))
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(publicKey)
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+)
+
+type command struct {
+	usage string
+	run   func(args []string) error
+}
+
+var commands = map[string]command{
+	"keygen":   {usage: "generate an RSA key pair in DANA formats", run: runKeygen},
+	"pubkey":   {usage: "derive the public key from a private key", run: runPubkey},
+	"token":    {usage: "request a B2B access token", run: runToken},
+	"query":    {usage: "query a payment: query <ref>", run: runQuery},
+	"cancel":   {usage: "cancel a payment: cancel <ref>", run: runCancel},
+	"refund":   {usage: "refund a payment: refund <ref> <amount>", run: runRefund},
+	"qris":     {usage: "generate a QRIS code: qris <amount>", run: runQris},
+	"balance":  {usage: "inquire a customer balance", run: runBalance},
+	"history":  {usage: "list a customer transaction history", run: runHistory},
+	"auth-url": {usage: "build the customer authorization url", run: runAuthUrl},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	cmd, exist := commands[os.Args[1]]
+	if !exist {
+		usage()
+		os.Exit(2)
+	}
+
+	if err := cmd.run(os.Args[2:]); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	_, _ = fmt.Fprintln(os.Stderr, "usage: dana <command> [flags] [args]")
+	for _, name := range names {
+		_, _ = fmt.Fprintf(os.Stderr, "  %-10s %s\n", name, commands[name].usage)
+	}
+}
+
+func newFlagSet(name string) *flag.FlagSet {
+	return flag.NewFlagSet(name, flag.ContinueOnError)
+}
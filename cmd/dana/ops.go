@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/vannleonheart/dana-api-go"
+	"strings"
+)
+
+func runToken(args []string) error {
+	fs := newFlagSet("token")
+	opts := addClientFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	client, err := opts.client()
+	if err != nil {
+		return err
+	}
+
+	return opts.print(client.GetB2BAccessToken())
+}
+
+func runQuery(args []string) error {
+	fs := newFlagSet("query")
+	opts := addClientFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: dana query [flags] <partner-reference-no>")
+	}
+
+	client, err := opts.client()
+	if err != nil {
+		return err
+	}
+
+	return opts.print(client.QueryPayment(fs.Arg(0)))
+}
+
+func runCancel(args []string) error {
+	fs := newFlagSet("cancel")
+	opts := addClientFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: dana cancel [flags] <partner-reference-no>")
+	}
+
+	client, err := opts.client()
+	if err != nil {
+		return err
+	}
+
+	return opts.print(client.CancelOrder(fs.Arg(0)))
+}
+
+func runRefund(args []string) error {
+	fs := newFlagSet("refund")
+	opts := addClientFlags(fs)
+	currency := fs.String("currency", "IDR", "refund currency")
+	refundId := fs.String("refund-id", "", "partner refund no, generated when empty")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 2 {
+		return fmt.Errorf("usage: dana refund [flags] <partner-reference-no> <amount>")
+	}
+
+	client, err := opts.client()
+	if err != nil {
+		return err
+	}
+
+	if len(*refundId) == 0 {
+		*refundId = dana.NewNumericIDGenerator(0).Generate()
+	}
+
+	return opts.print(client.RefundOrder(fs.Arg(0), *refundId, *currency, fs.Arg(1)))
+}
+
+func runQris(args []string) error {
+	fs := newFlagSet("qris")
+	opts := addClientFlags(fs)
+	currency := fs.String("currency", "IDR", "payment currency")
+	referenceNo := fs.String("ref", "", "partner reference no, generated when empty")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: dana qris [flags] <amount>")
+	}
+
+	client, err := opts.client()
+	if err != nil {
+		return err
+	}
+
+	if len(*referenceNo) == 0 {
+		*referenceNo = dana.NewNumericIDGenerator(0).Generate()
+	}
+
+	return opts.print(client.GenerateQRIS(*currency, fs.Arg(0), *referenceNo))
+}
+
+func runBalance(args []string) error {
+	fs := newFlagSet("balance")
+	opts := addClientFlags(fs)
+	token := fs.String("customer-token", "", "customer access token")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if len(*token) == 0 {
+		return fmt.Errorf("-customer-token is required")
+	}
+
+	client, err := opts.client()
+	if err != nil {
+		return err
+	}
+
+	return opts.print(client.CustomerBalanceInquiry(nil, customerToken(*token), nil, nil))
+}
+
+func runHistory(args []string) error {
+	fs := newFlagSet("history")
+	opts := addClientFlags(fs)
+	token := fs.String("customer-token", "", "customer access token")
+	from := fs.String("from", "", "start date time, RFC3339")
+	to := fs.String("to", "", "end date time, RFC3339")
+	pageSize := fs.Int("page-size", 0, "page size")
+	pageNumber := fs.Int("page", 0, "page number")
+	all := fs.Bool("all", false, "fetch every page")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if len(*token) == 0 {
+		return fmt.Errorf("-customer-token is required")
+	}
+
+	client, err := opts.client()
+	if err != nil {
+		return err
+	}
+
+	request := dana.TransactionHistoryRequest{}
+	if len(*from) > 0 {
+		request.FromDateTime = from
+	}
+
+	if len(*to) > 0 {
+		request.ToDateTime = to
+	}
+
+	if *pageSize > 0 {
+		request.PageSize = pageSize
+	}
+
+	if *pageNumber > 0 {
+		request.PageNumber = pageNumber
+	}
+
+	if !*all {
+		_, result, err := client.TransactionHistory(&request, customerToken(*token))
+
+		return opts.print(result, err)
+	}
+
+	var items []dana.TransactionHistoryItem
+
+	err = client.EachTransactionHistory(context.Background(), request, customerToken(*token), func(item dana.TransactionHistoryItem) error {
+		items = append(items, item)
+
+		return nil
+	})
+
+	return opts.print(items, err)
+}
+
+func runAuthUrl(args []string) error {
+	fs := newFlagSet("auth-url")
+	opts := addClientFlags(fs)
+	redirectUrl := fs.String("redirect", "", "redirect url after customer authorization")
+	scopes := fs.String("scopes", "", "comma separated scopes, defaults are used when empty")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if len(*redirectUrl) == 0 {
+		return fmt.Errorf("-redirect is required")
+	}
+
+	client, err := opts.client()
+	if err != nil {
+		return err
+	}
+
+	var requested *[]string
+	if len(*scopes) > 0 {
+		var list []string
+		for _, scope := range strings.Split(*scopes, ",") {
+			if scope = strings.TrimSpace(scope); len(scope) > 0 {
+				list = append(list, scope)
+			}
+		}
+
+		requested = &list
+	}
+
+	externalId, requestUrl, err := client.GetCustomerAuthCode(requested, *redirectUrl)
+	if err != nil {
+		return err
+	}
+
+	return opts.print(map[string]string{
+		"externalId": *externalId,
+		"url":        *requestUrl,
+	}, nil)
+}
+
+func customerToken(token string) *dana.AccessToken {
+	return &dana.AccessToken{AccessToken: token, TokenType: "Bearer"}
+}
@@ -0,0 +1,215 @@
+package dana
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	EnvironmentSandbox    = "sandbox"
+	EnvironmentProduction = "production"
+)
+
+type Environment struct {
+	ApiUrl    string `json:"api_url"`
+	WebUrl    string `json:"web_url"`
+	PublicKey string `json:"public_key"`
+}
+
+// The presets do not ship DANA's public key. DANA issues it per merchant in the
+// merchant dashboard, so callers must set Config.PublicKey or Config.PublicKeyFile
+// themselves before verifying notifications.
+var (
+	environmentMutex sync.RWMutex
+	environments     = map[string]Environment{
+		EnvironmentSandbox: {
+			ApiUrl: "https://api.sandbox.dana.id",
+			WebUrl: "https://m.sandbox.dana.id",
+		},
+		EnvironmentProduction: {
+			ApiUrl: "https://api.saas.dana.id",
+			WebUrl: "https://m.dana.id",
+		},
+	}
+)
+
+func RegisterEnvironment(name string, environment Environment) {
+	environmentMutex.Lock()
+	defer environmentMutex.Unlock()
+
+	environments[strings.ToLower(strings.TrimSpace(name))] = environment
+}
+
+func GetEnvironment(name string) (*Environment, error) {
+	environmentMutex.RLock()
+	defer environmentMutex.RUnlock()
+
+	environment, exist := environments[strings.ToLower(strings.TrimSpace(name))]
+	if !exist {
+		return nil, fmt.Errorf("unknown environment %s", name)
+	}
+
+	return &environment, nil
+}
+
+func (cfg *Config) ApplyEnvironment() error {
+	if len(strings.TrimSpace(cfg.Environment)) == 0 {
+		return nil
+	}
+
+	environment, err := GetEnvironment(cfg.Environment)
+	if err != nil {
+		return err
+	}
+
+	if len(cfg.ApiUrl) == 0 {
+		cfg.ApiUrl = environment.ApiUrl
+	}
+
+	if len(cfg.WebUrl) == 0 {
+		cfg.WebUrl = environment.WebUrl
+	}
+
+	if len(cfg.PublicKey) == 0 {
+		cfg.PublicKey = environment.PublicKey
+	}
+
+	return nil
+}
+
+func (cfg Config) Validate() error {
+	return cfg.validate(true, true)
+}
+
+func (c *Client) Validate() error {
+	return c.Config.validate(c.signer == nil, c.symmetricSigner == nil)
+}
+
+func (cfg Config) validate(requirePrivateKey, requireClientSecret bool) error {
+	var errs []error
+
+	if len(strings.TrimSpace(cfg.MerchantId)) == 0 {
+		errs = append(errs, fmt.Errorf("merchant_id is required"))
+	}
+
+	if len(strings.TrimSpace(cfg.ClientId)) == 0 {
+		errs = append(errs, fmt.Errorf("client_id is required"))
+	}
+
+	if requireClientSecret && len(strings.TrimSpace(cfg.ClientSecret)) == 0 {
+		errs = append(errs, fmt.Errorf("client_secret is required"))
+	}
+
+	if len(strings.TrimSpace(cfg.PrivateKey)) == 0 {
+		if requirePrivateKey {
+			errs = append(errs, fmt.Errorf("private_key is required"))
+		}
+	} else if _, err := ParsePrivateKey([]byte(cfg.PrivateKey)); err != nil {
+		errs = append(errs, fmt.Errorf("private_key is invalid: %w", err))
+	}
+
+	if len(strings.TrimSpace(cfg.PublicKey)) > 0 {
+		if _, err := ParsePublicKey([]byte(cfg.PublicKey)); err != nil {
+			errs = append(errs, fmt.Errorf("public_key is invalid: %w", err))
+		}
+	}
+
+	if len(strings.TrimSpace(cfg.NextPublicKey)) > 0 {
+		if _, err := ParsePublicKey([]byte(cfg.NextPublicKey)); err != nil {
+			errs = append(errs, fmt.Errorf("next_public_key is invalid: %w", err))
+		}
+	}
+
+	if len(strings.TrimSpace(cfg.Environment)) > 0 {
+		if _, err := GetEnvironment(cfg.Environment); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if err := validateBaseUrl("api_url", cfg.ApiUrl); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := validateBaseUrl("web_url", cfg.WebUrl); err != nil {
+		errs = append(errs, err)
+	}
+
+	if apiEnv, webEnv := environmentOf(cfg.ApiUrl, true), environmentOf(cfg.WebUrl, false); len(apiEnv) > 0 && len(webEnv) > 0 && apiEnv != webEnv {
+		errs = append(errs, fmt.Errorf("api_url is a %s url but web_url is a %s url", apiEnv, webEnv))
+	}
+
+	if len(strings.TrimSpace(cfg.Timezone)) > 0 {
+		if _, err := time.LoadLocation(strings.TrimSpace(cfg.Timezone)); err != nil && strings.TrimSpace(cfg.Timezone) != defaultTimezone {
+			errs = append(errs, fmt.Errorf("timezone is invalid: %w", err))
+		}
+	}
+
+	if cfg.DefaultExpireTime != nil && *cfg.DefaultExpireTime <= 0 {
+		errs = append(errs, fmt.Errorf("default_expire_time must be greater than zero"))
+	}
+
+	if cfg.Retry != nil && cfg.Retry.MaxAttempts < 0 {
+		errs = append(errs, fmt.Errorf("retry.max_attempts must not be negative"))
+	}
+
+	if cfg.CircuitBreaker != nil && (cfg.CircuitBreaker.FailureThreshold < 0 || cfg.CircuitBreaker.CooldownSeconds < 0) {
+		errs = append(errs, fmt.Errorf("circuit_breaker values must not be negative"))
+	}
+
+	if cfg.RateLimit != nil {
+		if cfg.RateLimit.RequestsPerSecond < 0 || cfg.RateLimit.Burst < 0 || cfg.RateLimit.MaxInFlight < 0 {
+			errs = append(errs, fmt.Errorf("rate_limit values must not be negative"))
+		}
+
+		for name, rule := range cfg.RateLimit.Endpoints {
+			if rule.RequestsPerSecond < 0 || rule.Burst < 0 || rule.MaxInFlight < 0 {
+				errs = append(errs, fmt.Errorf("rate_limit.endpoints.%s values must not be negative", name))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func validateBaseUrl(name, value string) error {
+	if len(strings.TrimSpace(value)) == 0 {
+		return fmt.Errorf("%s is required", name)
+	}
+
+	u, err := url.Parse(value)
+	if err != nil {
+		return fmt.Errorf("%s is invalid: %w", name, err)
+	}
+
+	if (u.Scheme != "https" && u.Scheme != "http") || len(u.Host) == 0 {
+		return fmt.Errorf("%s must be an absolute http or https url", name)
+	}
+
+	if strings.HasSuffix(value, "/") {
+		return fmt.Errorf("%s must not end with a slash", name)
+	}
+
+	return nil
+}
+
+func environmentOf(value string, api bool) string {
+	environmentMutex.RLock()
+	defer environmentMutex.RUnlock()
+
+	for name, environment := range environments {
+		preset := environment.WebUrl
+		if api {
+			preset = environment.ApiUrl
+		}
+
+		if len(preset) > 0 && strings.TrimRight(value, "/") == preset {
+			return name
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,19 @@
+package dana
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateRequiresClientSecretUnlessSymmetricSignerIsSet(t *testing.T) {
+	c := newRegistryClient(t, "m1", "p1")
+	c.Config.ClientSecret = ""
+
+	if err := c.Validate(); err == nil || !strings.Contains(err.Error(), "client_secret is required") {
+		t.Fatalf("got %v, want client_secret is required", err)
+	}
+
+	if err := c.WithSymmetricSigner(NewSymmetricSigner("injected")).Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
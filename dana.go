@@ -1,15 +1,109 @@
 package dana
 
 import (
-	"fmt"
-	"github.com/vannleonheart/goutil"
-	"time"
+	"context"
+	"log/slog"
+	"net/http"
 )
 
 func New(config Config) *Client {
-	return &Client{
-		Config: config,
+	err := config.ApplyEnvironment()
+
+	c := &Client{
+		Config:    config,
+		configErr: err,
+	}
+
+	if err != nil {
+		c.log(context.Background(), slog.LevelError, "error when apply environment",
+			slog.String("environment", config.Environment),
+			errorAttr(err),
+		)
 	}
+
+	return c
+}
+
+func (c *Client) SetContext(ctx context.Context) {
+	c.ctx = ctx
+}
+
+func (c *Client) ClearContext() {
+	c.ctx = nil
+}
+
+func (c *Client) WithContext(ctx context.Context) *Client {
+	c.SetContext(ctx)
+
+	return c
+}
+
+func (c *Client) SetHttpClient(httpClient *http.Client) {
+	c.httpClient = httpClient
+}
+
+func (c *Client) WithHttpClient(httpClient *http.Client) *Client {
+	c.SetHttpClient(httpClient)
+
+	return c
+}
+
+func (c *Client) SetLogger(logger Logger) {
+	c.logger = logger
+}
+
+func (c *Client) ClearLogger() {
+	c.logger = nil
+}
+
+func (c *Client) WithLogger(logger Logger) *Client {
+	c.SetLogger(logger)
+
+	return c
+}
+
+func (c *Client) SetTelemetry(telemetry *Telemetry) {
+	c.telemetry = telemetry
+}
+
+func (c *Client) WithTelemetry(telemetry *Telemetry) *Client {
+	c.SetTelemetry(telemetry)
+
+	return c
+}
+
+func (c *Client) SetClock(clock Clock) {
+	c.clock = clock
+}
+
+func (c *Client) ClearClock() {
+	c.clock = nil
+}
+
+func (c *Client) WithClock(clock Clock) *Client {
+	c.SetClock(clock)
+
+	return c
+}
+
+func (c *Client) SetSigner(signer Signer) {
+	c.signer = signer
+}
+
+func (c *Client) WithSigner(signer Signer) *Client {
+	c.SetSigner(signer)
+
+	return c
+}
+
+func (c *Client) SetSymmetricSigner(signer Signer) {
+	c.symmetricSigner = signer
+}
+
+func (c *Client) WithSymmetricSigner(signer Signer) *Client {
+	c.SetSymmetricSigner(signer)
+
+	return c
 }
 
 func (c *Client) SetB2BAccessToken(accessToken *AccessToken) {
@@ -124,10 +218,36 @@ func (c *Client) WithRequestId(requestId string) *Client {
 	return c
 }
 
-func (c *Client) SetGeneratedRequestId() {
-	requestId := fmt.Sprintf("%s%s", time.Now().Format("20060102"), GenerateRequestId(8, 16, goutil.NumCharset))
+func (c *Client) SetIDGenerator(generator IDGenerator) {
+	c.idGenerator = generator
+}
 
-	c.SetRequestId(requestId)
+func (c *Client) ClearIDGenerator() {
+	c.idGenerator = nil
+}
+
+func (c *Client) WithIDGenerator(generator IDGenerator) *Client {
+	c.SetIDGenerator(generator)
+
+	return c
+}
+
+func (c *Client) SetIdempotencyStore(store IdempotencyStore) {
+	c.idempotencyStore = store
+}
+
+func (c *Client) ClearIdempotencyStore() {
+	c.idempotencyStore = nil
+}
+
+func (c *Client) WithIdempotencyStore(store IdempotencyStore) *Client {
+	c.SetIdempotencyStore(store)
+
+	return c
+}
+
+func (c *Client) SetGeneratedRequestId() {
+	c.SetRequestId(c.getIDGenerator().Generate())
 }
 
 func (c *Client) WithGeneratedRequestId() *Client {
@@ -1,23 +1,45 @@
 package dana
 
 import (
-	"crypto"
-	"crypto/rand"
-	"crypto/rsa"
-	"crypto/sha256"
-	"crypto/x509"
-	"encoding/base64"
-	"errors"
-	"fmt"
-	"github.com/vannleonheart/goutil"
-	"strings"
+	"context"
+	"net/http"
 	"time"
 )
 
-func (c *Client) getChannelId() string {
+func (c *Client) getChannelId(ep endpoint) string {
+	if channelId, exist := c.Config.ChannelIds[ep.name]; exist && len(channelId) > 0 {
+		return channelId
+	}
+
+	if len(c.Config.ChannelId) > 0 {
+		return c.Config.ChannelId
+	}
+
+	if len(ep.channelId) > 0 {
+		return ep.channelId
+	}
+
 	return defaultChannelId
 }
 
+func (c *Client) getHttpClient() *http.Client {
+	if c.httpClient == nil {
+		return http.DefaultClient
+	}
+
+	return c.httpClient
+}
+
+func (c *Client) getContext() context.Context {
+	defer c.ClearContext()
+
+	if c.ctx == nil {
+		return context.Background()
+	}
+
+	return c.ctx
+}
+
 func (c *Client) getCustomerAccessToken(accessToken *AccessToken) *AccessToken {
 	if accessToken != nil {
 		c.SetCustomerAccessToken(accessToken)
@@ -87,6 +109,20 @@ func (c *Client) getLongitude() string {
 	return lon
 }
 
+func (c *Client) getMcc(mcc *string) string {
+	currentMcc := defaultMcc
+
+	if len(c.Config.Mcc) > 0 {
+		currentMcc = c.Config.Mcc
+	}
+
+	if mcc != nil {
+		currentMcc = *mcc
+	}
+
+	return currentMcc
+}
+
 func (c *Client) getExpireTime(customExpireTime *int64) string {
 	expireTime := defaultExpireTime
 
@@ -102,83 +138,53 @@ func (c *Client) getExpireTime(customExpireTime *int64) string {
 }
 
 func (c *Client) getCurrentTime() time.Time {
-	now := time.Now()
-	currentTimezone := defaultTimezone
-	configTimezone := strings.TrimSpace(c.Config.Timezone)
-
-	if len(configTimezone) > 0 {
-		currentTimezone = configTimezone
-	}
+	now := c.getClock().Now()
 
-	loc, err := time.LoadLocation(currentTimezone)
-	if err == nil {
-		now = now.In(loc)
+	if c.isSkewEnabled() {
+		now = now.Add(c.ClockSkew())
 	}
 
-	return now
+	return now.In(c.getLocation())
 }
 
 func (c *Client) getTimestamp() string {
 	return c.getCurrentTime().Format(TimestampFormat)
 }
 
-func (c *Client) sign(strToSign string) (*string, error) {
-	pk, err := c.parsePrivateKey(c.Config.PrivateKey)
-	if err != nil {
-		return nil, err
-	}
-
-	h := sha256.New()
-	if _, err = h.Write([]byte(strToSign)); err != nil {
-		return nil, err
-	}
+func (c *Client) getSigner(ep endpoint) (Signer, error) {
+	if ep.signature == signatureSymmetric {
+		if c.symmetricSigner != nil {
+			return c.symmetricSigner, nil
+		}
 
-	signed, err := rsa.SignPKCS1v15(rand.Reader, pk, crypto.SHA256, h.Sum(nil))
-	if err != nil {
-		return nil, err
+		return NewSymmetricSigner(c.Config.ClientSecret), nil
 	}
 
-	signature := base64.StdEncoding.EncodeToString(signed)
-
-	return &signature, nil
-}
-
-func (c *Client) parsePrivateKey(pvKey string) (*rsa.PrivateKey, error) {
-	keyBytes, err := base64.StdEncoding.DecodeString(pvKey)
-	if err != nil {
-		return nil, err
+	if c.signer != nil {
+		return c.signer, nil
 	}
 
-	pvtKey, err := x509.ParsePKCS1PrivateKey(keyBytes)
-	if err == nil {
-		return pvtKey, nil
-	}
+	c.keyMutex.Lock()
+	defer c.keyMutex.Unlock()
 
-	key, err2 := x509.ParsePKCS8PrivateKey(keyBytes)
-	if err2 == nil {
-		valPvtKey, ok := key.(*rsa.PrivateKey)
-		if ok {
-			return valPvtKey, nil
+	if c.parsedSigner == nil || c.parsedKey != c.Config.PrivateKey {
+		pk, err := ParsePrivateKey([]byte(c.Config.PrivateKey))
+		if err != nil {
+			return nil, err
 		}
 
-		return nil, fmt.Errorf("expected *rsa.PrivateKey, got %T", key)
+		c.parsedKey = c.Config.PrivateKey
+		c.parsedSigner = NewAsymmetricSigner(pk)
 	}
 
-	return nil, errors.Join(err, err2)
+	return c.parsedSigner, nil
 }
 
-func (c *Client) log(level string, data interface{}) {
-	if c.Config.Log != nil && c.Config.Log.Enable {
-		if c.Config.Log.Level == "error" && level != "error" {
-			return
-		}
-
-		msg := map[string]interface{}{
-			"timestamp": c.getTimestamp(),
-			"level":     level,
-			"data":      data,
-		}
-
-		_ = goutil.WriteJsonToFile(msg, c.Config.Log.Path, c.Config.Log.Filename, c.Config.Log.Extension, c.Config.Log.Rotation)
+func (c *Client) sign(ep endpoint, strToSign string) (string, error) {
+	signer, err := c.getSigner(ep)
+	if err != nil {
+		return "", err
 	}
+
+	return signer.Sign(strToSign)
 }
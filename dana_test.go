@@ -0,0 +1,57 @@
+package dana
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"sync"
+	"testing"
+)
+
+var (
+	testKeyOnce sync.Once
+	testKeys    [2]*rsa.PrivateKey
+	testKeyErr  error
+)
+
+func testKey(t *testing.T, i int) *rsa.PrivateKey {
+	t.Helper()
+
+	testKeyOnce.Do(func() {
+		for k := range testKeys {
+			if testKeys[k], testKeyErr = rsa.GenerateKey(rand.Reader, 2048); testKeyErr != nil {
+				return
+			}
+		}
+	})
+
+	if testKeyErr != nil {
+		t.Fatal(testKeyErr)
+	}
+
+	return testKeys[i]
+}
+
+func testPrivateKey(t *testing.T) *rsa.PrivateKey {
+	return testKey(t, 0)
+}
+
+func testNextPrivateKey(t *testing.T) *rsa.PrivateKey {
+	return testKey(t, 1)
+}
+
+func testPublicKey(t *testing.T, key *rsa.PrivateKey) string {
+	t.Helper()
+
+	publicKey, err := (&KeyPair{PrivateKey: key}).PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return publicKey
+}
+
+func newTestClient(t *testing.T, apiUrl string) *Client {
+	t.Helper()
+
+	return New(Config{ApiUrl: apiUrl, MerchantId: "m1", ClientId: "p1", ClientSecret: "s1"}).WithSigner(NewAsymmetricSigner(testPrivateKey(t)))
+}
@@ -0,0 +1,206 @@
+package dana
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type SignatureDiagnosisInput struct {
+	Method       string
+	Path         string
+	Body         []byte
+	Timestamp    string
+	Signature    string
+	AccessToken  string
+	PublicKey    string
+	PrivateKey   string
+	ClientSecret string
+}
+
+type SignatureVariant struct {
+	Name         string
+	Symmetric    bool
+	StringToSign string
+	BodyHash     string
+	Verified     bool
+}
+
+type SignatureDiagnosis struct {
+	StringToSign string
+	BodyHash     string
+	Verified     bool
+	Match        *SignatureVariant
+	Variants     []SignatureVariant
+}
+
+func DiagnoseSignature(input SignatureDiagnosisInput) (*SignatureDiagnosis, error) {
+	method := strings.ToUpper(strings.TrimSpace(input.Method))
+	if len(method) == 0 {
+		method = http.MethodPost
+	}
+
+	publicKey, err := diagnosisPublicKey(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if publicKey == nil && len(input.ClientSecret) == 0 {
+		return nil, fmt.Errorf("public key, private key or client secret is required to verify signature")
+	}
+
+	bodies, err := diagnosisBodies(input.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := diagnosisPaths(input.Path)
+
+	var modes []bool
+	if publicKey != nil {
+		modes = append(modes, false)
+	}
+
+	if len(input.ClientSecret) > 0 {
+		modes = append(modes, true)
+	}
+
+	result := &SignatureDiagnosis{}
+
+	for _, symmetric := range modes {
+		for _, body := range bodies {
+			for _, path := range paths {
+				bodyHash := HashRequestBody(body.data)
+				variant := SignatureVariant{
+					Name:         fmt.Sprintf("%s, %s", body.name, path.name),
+					Symmetric:    symmetric,
+					StringToSign: buildStringToSign(method, path.path, input.AccessToken, bodyHash, input.Timestamp, symmetric),
+					BodyHash:     bodyHash,
+				}
+
+				if symmetric {
+					variant.Name = fmt.Sprintf("symmetric, %s", variant.Name)
+					variant.Verified = verifySymmetric(input.ClientSecret, variant.StringToSign, input.Signature)
+				} else {
+					variant.Name = fmt.Sprintf("asymmetric, %s", variant.Name)
+					variant.Verified = VerifySignature(publicKey, variant.StringToSign, input.Signature) == nil
+				}
+
+				result.Variants = append(result.Variants, variant)
+			}
+		}
+	}
+
+	canonical := result.Variants[0]
+	result.StringToSign = canonical.StringToSign
+	result.BodyHash = canonical.BodyHash
+	result.Verified = canonical.Verified
+
+	for i := range result.Variants {
+		if result.Variants[i].Verified {
+			result.Match = &result.Variants[i]
+
+			break
+		}
+	}
+
+	return result, nil
+}
+
+func (d *SignatureDiagnosis) String() string {
+	sb := &strings.Builder{}
+
+	_, _ = fmt.Fprintf(sb, "string to sign: %s\n", d.StringToSign)
+	_, _ = fmt.Fprintf(sb, "body hash:      %s\n", d.BodyHash)
+	_, _ = fmt.Fprintf(sb, "verified:       %t\n", d.Verified)
+
+	switch {
+	case d.Verified:
+	case d.Match != nil:
+		_, _ = fmt.Fprintf(sb, "matched variant: %s\n", d.Match.Name)
+		_, _ = fmt.Fprintf(sb, "matched string to sign: %s\n", d.Match.StringToSign)
+	default:
+		_, _ = fmt.Fprintln(sb, "no variant verifies, check the key pair, client secret and timestamp")
+	}
+
+	return sb.String()
+}
+
+type diagnosisBody struct {
+	name string
+	data []byte
+}
+
+type diagnosisPath struct {
+	name string
+	path string
+}
+
+func diagnosisPublicKey(input SignatureDiagnosisInput) (*rsa.PublicKey, error) {
+	if len(strings.TrimSpace(input.PublicKey)) > 0 {
+		return ParsePublicKey([]byte(input.PublicKey))
+	}
+
+	if len(strings.TrimSpace(input.PrivateKey)) > 0 {
+		pk, err := ParsePrivateKey([]byte(input.PrivateKey))
+		if err != nil {
+			return nil, err
+		}
+
+		return &pk.PublicKey, nil
+	}
+
+	return nil, nil
+}
+
+func diagnosisBodies(raw []byte) ([]diagnosisBody, error) {
+	minified := &bytes.Buffer{}
+	if len(bytes.TrimSpace(raw)) > 0 {
+		if err := json.Compact(minified, raw); err != nil {
+			return nil, fmt.Errorf("body is not valid json: %w", err)
+		}
+	}
+
+	escaped := &bytes.Buffer{}
+	json.HTMLEscape(escaped, minified.Bytes())
+
+	unescaped := strings.NewReplacer(`\u0026`, "&", `\u003c`, "<", `\u003e`, ">").Replace(minified.String())
+
+	return []diagnosisBody{
+		{name: "minified body", data: minified.Bytes()},
+		{name: "raw body", data: raw},
+		{name: "html escaped body", data: escaped.Bytes()},
+		{name: "html unescaped body", data: []byte(unescaped)},
+	}, nil
+}
+
+func diagnosisPaths(path string) []diagnosisPath {
+	path = strings.TrimSpace(path)
+	trimmed := strings.TrimPrefix(path, "/")
+
+	paths := []diagnosisPath{
+		{name: "path with leading slash", path: fmt.Sprintf("/%s", trimmed)},
+		{name: "path without leading slash", path: trimmed},
+	}
+
+	if idx := strings.Index(trimmed, "v1.0/"); idx > 0 {
+		paths = append(paths, diagnosisPath{name: "path without prefix", path: fmt.Sprintf("/%s", trimmed[idx:])})
+	} else if idx < 0 {
+		paths = append(paths, diagnosisPath{name: "path with v1.0 prefix", path: fmt.Sprintf("/v1.0/%s", trimmed)})
+	}
+
+	return paths
+}
+
+func verifySymmetric(secret, stringToSign, signature string) bool {
+	expected, err := NewSymmetricSigner(secret).Sign(stringToSign)
+	if err != nil {
+		return false
+	}
+
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
@@ -0,0 +1,105 @@
+package dana
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+const diagnoseTimestamp = "2024-01-01T00:00:00+07:00"
+
+var diagnoseBody = []byte(`{"merchantId": "m1", "url": "https://m.dana.id/finish?order=1&status=paid"}`)
+
+func diagnoseSign(t *testing.T, signer Signer, body []byte, symmetric bool) string {
+	t.Helper()
+
+	stringToSign := buildStringToSign("POST", "/"+URLDirectDebitPayment, "token", HashRequestBody(body), diagnoseTimestamp, symmetric)
+
+	signature, err := signer.Sign(stringToSign)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return signature
+}
+
+func TestDiagnoseSignatureFindsVariant(t *testing.T) {
+	minified := &bytes.Buffer{}
+	_ = json.Compact(minified, diagnoseBody)
+	htmlEscaped := &bytes.Buffer{}
+	json.HTMLEscape(htmlEscaped, minified.Bytes())
+
+	tests := []struct {
+		name      string
+		signature string
+		input     SignatureDiagnosisInput
+		verified  bool
+		match     string
+	}{
+		{
+			name:      "canonical asymmetric",
+			signature: diagnoseSign(t, NewAsymmetricSigner(testPrivateKey(t)), minified.Bytes(), false),
+			input:     SignatureDiagnosisInput{PublicKey: testPublicKey(t, testPrivateKey(t))},
+			verified:  true,
+			match:     "asymmetric, minified body, path with leading slash",
+		},
+		{
+			name:      "html escaped body",
+			signature: diagnoseSign(t, NewAsymmetricSigner(testPrivateKey(t)), htmlEscaped.Bytes(), false),
+			input:     SignatureDiagnosisInput{PublicKey: testPublicKey(t, testPrivateKey(t))},
+			match:     "asymmetric, html escaped body, path with leading slash",
+		},
+		{
+			name:      "mismatching public key",
+			signature: diagnoseSign(t, NewAsymmetricSigner(testPrivateKey(t)), minified.Bytes(), false),
+			input:     SignatureDiagnosisInput{PublicKey: testPublicKey(t, testNextPrivateKey(t))},
+		},
+		{
+			name:      "symmetric with key and secret",
+			signature: diagnoseSign(t, NewSymmetricSigner("secret"), minified.Bytes(), true),
+			input:     SignatureDiagnosisInput{PublicKey: testPublicKey(t, testPrivateKey(t)), ClientSecret: "secret"},
+			match:     "symmetric, minified body, path with leading slash",
+		},
+		{
+			name:      "mismatching client secret",
+			signature: diagnoseSign(t, NewSymmetricSigner("secret"), minified.Bytes(), true),
+			input:     SignatureDiagnosisInput{ClientSecret: "other"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := tt.input
+			input.Method = "post"
+			input.Path = URLDirectDebitPayment
+			input.Body = diagnoseBody
+			input.Timestamp = diagnoseTimestamp
+			input.AccessToken = "token"
+			input.Signature = tt.signature
+
+			diagnosis, err := DiagnoseSignature(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diagnosis.Verified != tt.verified {
+				t.Fatalf("got verified %t, want %t", diagnosis.Verified, tt.verified)
+			}
+
+			match := ""
+			if diagnosis.Match != nil {
+				match = diagnosis.Match.Name
+			}
+
+			if match != tt.match {
+				t.Fatalf("got match %q, want %q", match, tt.match)
+			}
+		})
+	}
+}
+
+func TestDiagnoseSignatureRequiresKeyOrSecret(t *testing.T) {
+	if _, err := DiagnoseSignature(SignatureDiagnosisInput{Body: diagnoseBody}); err == nil {
+		t.Fatal("expected an error without key or secret")
+	}
+}
@@ -0,0 +1,175 @@
+package dana
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const dryRunAccessToken = "DRY-RUN-ACCESS-TOKEN"
+
+type PreparedRequest struct {
+	Endpoint     string
+	Method       string
+	Url          string
+	Headers      map[string]string
+	StringToSign string
+	Body         []byte
+	client       *Client
+	request      *Request
+}
+
+type dryRunKey struct{}
+
+type DryRunError struct {
+	Request *PreparedRequest
+}
+
+func (e *DryRunError) Error() string {
+	return fmt.Sprintf("dry run, %s %s was not sent", e.Request.Method, e.Request.Url)
+}
+
+func (c *Client) SetDryRun(dryRun bool) {
+	c.dryRun = dryRun
+}
+
+func (c *Client) WithDryRun(dryRun bool) *Client {
+	c.SetDryRun(dryRun)
+
+	return c
+}
+
+func (c *Client) Prepare(ctx context.Context, call func(ctx context.Context) error) (*PreparedRequest, error) {
+	err := call(context.WithValue(ctx, dryRunKey{}, true))
+	if prepared, ok := GetPreparedRequest(err); ok {
+		return prepared, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, fmt.Errorf("no dana request was prepared")
+}
+
+func GetPreparedRequest(err error) (*PreparedRequest, bool) {
+	var dryRunErr *DryRunError
+	if errors.As(err, &dryRunErr) {
+		return dryRunErr.Request, true
+	}
+
+	return nil, false
+}
+
+func (p *PreparedRequest) Send(ctx context.Context) (*Response, error) {
+	req := p.request
+	ep := req.endpoint
+
+	req.presigned = true
+
+	if ep.authType == authTypeB2B && req.accessToken != nil && req.accessToken.AccessToken == dryRunAccessToken {
+		if err := p.client.ensureB2BAccessToken(ctx); err != nil {
+			return nil, err
+		}
+
+		req.accessToken = p.client.b2bAccessToken
+		req.Headers["Authorization"] = fmt.Sprintf("Bearer %s", req.accessToken.AccessToken)
+		req.presigned = false
+	}
+
+	if req.presigned && !p.client.isPreparedTimestampValid(req.Headers["X-TIMESTAMP"]) {
+		req.presigned = false
+	}
+
+	req.dryRun = false
+
+	resp, err := p.send(ctx)
+
+	p.refresh()
+
+	return resp, err
+}
+
+func (p *PreparedRequest) send(ctx context.Context) (*Response, error) {
+	c := p.client
+	req := p.request
+
+	requestBody, ok := req.RequestBody.(map[string]interface{})
+	if req.Endpoint != endpointDirectDebitPayment.name || c.idempotencyStore == nil || !ok {
+		return c.execute(ctx, req)
+	}
+
+	var resp *Response
+
+	result, err := c.idempotentDirectDebitPayment(ctx, req.ReferenceNo, req.ExternalId, requestBody, func(ctx context.Context) (*DirectDebitPaymentResponse, error) {
+		var err error
+		if resp, err = c.execute(ctx, req); err != nil {
+			return nil, err
+		}
+
+		result, _ := req.result.(*DirectDebitPaymentResponse)
+
+		return result, nil
+	})
+
+	if resp == nil && result != nil {
+		body, marshalErr := json.Marshal(result)
+		if marshalErr != nil {
+			return nil, marshalErr
+		}
+
+		resp = &Response{StatusCode: http.StatusOK, Body: body, Result: result}
+	}
+
+	return resp, err
+}
+
+func (p *PreparedRequest) refresh() {
+	headers := make(map[string]string, len(p.request.Headers))
+	for k, v := range p.request.Headers {
+		headers[k] = v
+	}
+
+	p.Headers = headers
+	p.StringToSign = p.request.StringToSign
+}
+
+func (c *Client) isPreparedTimestampValid(timestamp string) bool {
+	t, err := time.Parse(TimestampFormat, timestamp)
+	if err != nil {
+		return false
+	}
+
+	return absDuration(c.getCurrentTime().Sub(t)) <= c.getSkewTolerance()
+}
+
+func newPreparedRequest(c *Client, req *Request) *PreparedRequest {
+	headers := make(map[string]string, len(req.Headers))
+	for k, v := range req.Headers {
+		headers[k] = v
+	}
+
+	return &PreparedRequest{
+		Endpoint:     req.Endpoint,
+		Method:       req.Method,
+		Url:          req.Url,
+		Headers:      headers,
+		StringToSign: req.StringToSign,
+		Body:         req.Body,
+		client:       c,
+		request:      req,
+	}
+}
+
+func (c *Client) isDryRunContext(ctx context.Context) bool {
+	return c.dryRun || ctx.Value(dryRunKey{}) != nil
+}
+
+func isDryRun(err error) bool {
+	var dryRunErr *DryRunError
+
+	return errors.As(err, &dryRunErr)
+}
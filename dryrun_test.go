@@ -0,0 +1,127 @@
+package dana
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newDryRunClient(t *testing.T, clock *FixedClock) (*Client, *http.Header) {
+	t.Helper()
+
+	sent := &http.Header{}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*sent = r.Header.Clone()
+
+		_ = json.NewEncoder(w).Encode(map[string]string{"responseCode": "2005500"})
+	}))
+	t.Cleanup(srv.Close)
+
+	c := newTestClient(t, srv.URL).WithClock(clock)
+
+	return c, sent
+}
+
+func prepareQuery(t *testing.T, c *Client) *PreparedRequest {
+	t.Helper()
+
+	prepared, err := c.Prepare(context.Background(), func(ctx context.Context) error {
+		_, err := c.WithContext(ctx).QueryPayment("ref-1")
+
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return prepared
+}
+
+func TestPreparedRequestSendsReviewedSignature(t *testing.T) {
+	clock := &FixedClock{Time: time.Now()}
+	c, sent := newDryRunClient(t, clock)
+	prepared := prepareQuery(t, c)
+
+	if _, err := prepared.Send(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := sent.Get("X-SIGNATURE"), prepared.Headers["X-SIGNATURE"]; got != want {
+		t.Fatalf("sent signature %s, prepared %s", got, want)
+	}
+}
+
+func TestPreparedRequestResignsExpiredTimestamp(t *testing.T) {
+	clock := &FixedClock{Time: time.Now()}
+	c, sent := newDryRunClient(t, clock)
+	prepared := prepareQuery(t, c)
+	reviewed := prepared.Headers["X-TIMESTAMP"]
+
+	clock.Time = clock.Time.Add(time.Hour)
+
+	if _, err := prepared.Send(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if sent.Get("X-TIMESTAMP") == reviewed {
+		t.Fatal("expired prepared request was sent with its original timestamp")
+	}
+
+	if got, want := prepared.Headers["X-TIMESTAMP"], sent.Get("X-TIMESTAMP"); got != want {
+		t.Fatalf("prepared timestamp %s was not refreshed to %s", got, want)
+	}
+
+	if got, want := prepared.Headers["X-SIGNATURE"], sent.Get("X-SIGNATURE"); got != want {
+		t.Fatalf("prepared signature %s was not refreshed to %s", got, want)
+	}
+}
+
+func TestDryRunBypassesCircuitBreaker(t *testing.T) {
+	c, _ := newDryRunClient(t, &FixedClock{Time: time.Now()})
+	c.Config.CircuitBreaker = &CircuitBreakerConfig{Enable: true, FailureThreshold: 1}
+	c.recordCircuit(context.Background(), endpointQueryPayment.name, circuitFailure, 1)
+
+	prepareQuery(t, c)
+
+	if got := c.CircuitState(endpointQueryPayment.name); got != CircuitOpen {
+		t.Fatalf("got state %s, want %s", got, CircuitOpen)
+	}
+}
+
+func TestPreparedPaymentSendUsesIdempotencyStore(t *testing.T) {
+	srv := newIdempotencyServer(t)
+	c, store := newIdempotencyClient(t, srv)
+
+	prepared, err := c.Prepare(context.Background(), func(ctx context.Context) error {
+		_, err := pay(c.WithContext(ctx), "10000.00")
+
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if record, _ := store.Get("m1:ref-1"); record != nil || srv.payments != 0 {
+		t.Fatalf("dry run touched the store or the api: %+v, %d payments", record, srv.payments)
+	}
+
+	if _, err = prepared.Send(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if record, _ := store.Get("m1:ref-1"); record == nil || record.Status != IdempotencyStatusCompleted {
+		t.Fatalf("got record %+v, want COMPLETED", record)
+	}
+
+	if _, err = pay(c, "10000.00"); err != nil {
+		t.Fatal(err)
+	}
+
+	if srv.payments != 1 {
+		t.Fatalf("got %d payments, want 1", srv.payments)
+	}
+}
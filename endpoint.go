@@ -0,0 +1,133 @@
+package dana
+
+import "net/http"
+
+const (
+	authTypeNone = iota
+	authTypeB2B
+	authTypeB2B2C
+	authTypeAccessToken
+)
+
+const (
+	signatureAsymmetric = iota
+	signatureSymmetric
+)
+
+type endpoint struct {
+	name      string
+	path      string
+	method    string
+	authType  int
+	signature int
+	channelId string
+	origin    bool
+	retryable bool
+}
+
+var (
+	endpointB2BAccessToken = endpoint{
+		name:      "GetB2BAccessToken",
+		path:      URLAccessToken,
+		method:    http.MethodPost,
+		authType:  authTypeAccessToken,
+		retryable: true,
+	}
+	endpointApplyToken = endpoint{
+		name:      "CustomerApplyToken",
+		path:      URLApplyToken,
+		method:    http.MethodPost,
+		authType:  authTypeAccessToken,
+		retryable: true,
+	}
+	endpointApplyOTT = endpoint{
+		name:      "CustomerApplyOTT",
+		path:      URLApplyOTT,
+		method:    http.MethodPost,
+		authType:  authTypeB2B2C,
+		channelId: customerChannelId,
+		retryable: true,
+	}
+	endpointUnbindToken = endpoint{
+		name:      "CustomerUnbindAccount",
+		path:      URLUnbindToken,
+		method:    http.MethodPost,
+		authType:  authTypeB2B2C,
+		channelId: customerChannelId,
+		retryable: true,
+	}
+	endpointBalanceInquiry = endpoint{
+		name:      "CustomerBalanceInquiry",
+		path:      URLBalanceInquiry,
+		method:    http.MethodPost,
+		authType:  authTypeB2B2C,
+		channelId: customerChannelId,
+		retryable: true,
+	}
+	endpointTransactionList = endpoint{
+		name:      "TransactionHistory",
+		path:      URLTransactionList,
+		method:    http.MethodPost,
+		authType:  authTypeB2B2C,
+		channelId: customerChannelId,
+		retryable: true,
+	}
+	endpointTransactionDetail = endpoint{
+		name:      "TransactionHistoryDetail",
+		path:      URLTransactionDetail,
+		method:    http.MethodPost,
+		authType:  authTypeB2B2C,
+		channelId: customerChannelId,
+		retryable: true,
+	}
+	endpointQuickPay = endpoint{
+		name:      "QuickPay",
+		path:      URLQuickPay,
+		method:    http.MethodPost,
+		authType:  authTypeB2B,
+		signature: signatureSymmetric,
+	}
+	endpointDirectDebitPayment = endpoint{
+		name:     "DirectDebitPayment",
+		path:     URLDirectDebitPayment,
+		method:   http.MethodPost,
+		authType: authTypeNone,
+	}
+	endpointCancelPayment = endpoint{
+		name:      "CancelOrder",
+		path:      URLCancelPayment,
+		method:    http.MethodPost,
+		authType:  authTypeNone,
+		retryable: true,
+	}
+	endpointQueryPayment = endpoint{
+		name:      "QueryPayment",
+		path:      URLQueryPayment,
+		method:    http.MethodPost,
+		authType:  authTypeNone,
+		retryable: true,
+	}
+	endpointGenerateQRIS = endpoint{
+		name:      "GenerateQRIS",
+		path:      URLGenerateQRIS,
+		method:    http.MethodPost,
+		authType:  authTypeNone,
+		retryable: true,
+	}
+	endpointFinishNotify = endpoint{
+		name:      "FinishNotify",
+		path:      URLFinishNotify,
+		method:    http.MethodPost,
+		authType:  authTypeNone,
+		origin:    true,
+		retryable: true,
+	}
+	endpointRefund = endpoint{
+		name:      "RefundOrder",
+		path:      URLRefund,
+		method:    http.MethodPost,
+		authType:  authTypeNone,
+		channelId: customerChannelId,
+		origin:    true,
+	}
+)
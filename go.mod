@@ -2,4 +2,19 @@ module github.com/vannleonheart/dana-api-go
 
 go 1.21
 
-require github.com/vannleonheart/goutil v0.0.0-20240727234225-5b50bf3dbf9a
+require (
+	github.com/vannleonheart/goutil v0.0.0-20240727234225-5b50bf3dbf9a
+	go.opentelemetry.io/otel v1.28.0
+	go.opentelemetry.io/otel/metric v1.28.0
+	go.opentelemetry.io/otel/sdk v1.28.0
+	go.opentelemetry.io/otel/sdk/metric v1.28.0
+	go.opentelemetry.io/otel/trace v1.28.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/go-logr/logr v1.4.2 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	golang.org/x/sys v0.21.0 // indirect
+)
@@ -0,0 +1,117 @@
+package dana
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var ErrHistoryDone = errors.New("no more transaction history pages")
+
+type HistoryIterator struct {
+	client      *Client
+	request     TransactionHistoryRequest
+	accessToken *AccessToken
+	pageNumber  int
+	pageSize    int
+	done        bool
+}
+
+func (c *Client) NewHistoryIterator(request TransactionHistoryRequest, customerAccessToken *AccessToken) *HistoryIterator {
+	pageNumber := 1
+	if request.PageNumber != nil && *request.PageNumber > 0 {
+		pageNumber = *request.PageNumber
+	}
+
+	pageSize := defaultPageSize
+	if request.PageSize != nil && *request.PageSize > 0 {
+		pageSize = *request.PageSize
+	}
+
+	return &HistoryIterator{
+		client:      c,
+		request:     request,
+		accessToken: c.getCustomerAccessToken(customerAccessToken),
+		pageNumber:  pageNumber,
+		pageSize:    pageSize,
+	}
+}
+
+func (it *HistoryIterator) Next(ctx context.Context) (*TransactionHistoryResponse, error) {
+	if it.done {
+		return nil, ErrHistoryDone
+	}
+
+	pageNumber := it.pageNumber
+	pageSize := it.pageSize
+	request := it.request
+	request.PageNumber = &pageNumber
+	request.PageSize = &pageSize
+
+	result, err := it.client.transactionHistory(ctx, &request, it.client.getIDGenerator().Generate(), it.accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if !result.IsSuccess() {
+		it.done = true
+
+		return nil, fmt.Errorf("transaction history page %d failed: %s %s", pageNumber, result.ResponseCode, result.ResponseMessage)
+	}
+
+	it.pageNumber++
+
+	if result.AdditionalInfo != nil && result.AdditionalInfo.Paginator != nil && result.AdditionalInfo.Paginator.TotalPage > 0 {
+		it.done = it.pageNumber > int(result.AdditionalInfo.Paginator.TotalPage)
+	} else {
+		it.done = len(result.DetailData) < pageSize
+	}
+
+	if len(result.DetailData) == 0 {
+		it.done = true
+
+		return nil, ErrHistoryDone
+	}
+
+	return result, nil
+}
+
+func (c *Client) EachTransactionHistory(ctx context.Context, request TransactionHistoryRequest, customerAccessToken *AccessToken, fn func(item TransactionHistoryItem) error) error {
+	it := c.NewHistoryIterator(request, customerAccessToken)
+
+	for {
+		page, err := it.Next(ctx)
+		if errors.Is(err, ErrHistoryDone) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		for _, item := range page.DetailData {
+			if err = fn(item); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (c *Client) ExportTransactionHistory(ctx context.Context, w io.Writer, request TransactionHistoryRequest, customerAccessToken *AccessToken) (int, error) {
+	encoder := json.NewEncoder(w)
+	count := 0
+
+	err := c.EachTransactionHistory(ctx, request, customerAccessToken, func(item TransactionHistoryItem) error {
+		if err := encoder.Encode(item); err != nil {
+			return err
+		}
+
+		count++
+
+		return nil
+	})
+
+	return count, err
+}
@@ -0,0 +1,283 @@
+package dana
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/vannleonheart/goutil"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultPendingTimeout = 5 * time.Minute
+
+var (
+	ErrIdempotencyConflict   = errors.New("partner reference no was already used with different parameters")
+	ErrIdempotencyInProgress = errors.New("payment with the same partner reference no is still in progress")
+)
+
+type PaymentRecoveredError struct {
+	ReferenceNo string
+	Query       *QueryPaymentResponse
+}
+
+func (e *PaymentRecoveredError) Error() string {
+	return fmt.Sprintf("payment %s was already submitted, use the recovered query result", e.ReferenceNo)
+}
+
+type IdempotencyRecord struct {
+	Fingerprint string    `json:"fingerprint"`
+	Status      string    `json:"status"`
+	ExternalId  string    `json:"external_id"`
+	Response    []byte    `json:"response,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type IdempotencyStore interface {
+	Get(key string) (*IdempotencyRecord, error)
+	Create(key string, record IdempotencyRecord) (bool, error)
+	Update(key string, record IdempotencyRecord) error
+	CompareAndSwap(key string, old, new IdempotencyRecord) (bool, error)
+	Delete(key string) error
+}
+
+type MemoryIdempotencyStore struct {
+	mutex   sync.Mutex
+	ttl     time.Duration
+	records map[string]IdempotencyRecord
+}
+
+func NewMemoryIdempotencyStore(ttl time.Duration) *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{
+		ttl:     ttl,
+		records: map[string]IdempotencyRecord{},
+	}
+}
+
+func (s *MemoryIdempotencyStore) Get(key string) (*IdempotencyRecord, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	record, ok := s.load(key)
+	if !ok {
+		return nil, nil
+	}
+
+	return &record, nil
+}
+
+func (s *MemoryIdempotencyStore) Create(key string, record IdempotencyRecord) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.load(key); ok {
+		return false, nil
+	}
+
+	s.records[key] = record
+
+	return true, nil
+}
+
+func (s *MemoryIdempotencyStore) Update(key string, record IdempotencyRecord) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.records[key] = record
+
+	return nil
+}
+
+func (s *MemoryIdempotencyStore) CompareAndSwap(key string, old, new IdempotencyRecord) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	current, ok := s.load(key)
+	if !ok || !current.sameVersion(old) {
+		return false, nil
+	}
+
+	s.records[key] = new
+
+	return true, nil
+}
+
+func (s *MemoryIdempotencyStore) Delete(key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.records, key)
+
+	return nil
+}
+
+func (s *MemoryIdempotencyStore) load(key string) (IdempotencyRecord, bool) {
+	record, ok := s.records[key]
+	if ok && s.ttl > 0 && time.Since(record.CreatedAt) > s.ttl {
+		delete(s.records, key)
+
+		return IdempotencyRecord{}, false
+	}
+
+	return record, ok
+}
+
+func (r IdempotencyRecord) sameVersion(other IdempotencyRecord) bool {
+	return r.Fingerprint == other.Fingerprint && r.Status == other.Status && r.ExternalId == other.ExternalId && r.UpdatedAt.Equal(other.UpdatedAt)
+}
+
+func (c *Client) idempotentDirectDebitPayment(ctx context.Context, referenceNo, externalId string, requestBody map[string]interface{}, send func(ctx context.Context) (*DirectDebitPaymentResponse, error)) (*DirectDebitPaymentResponse, error) {
+	store := c.idempotencyStore
+	key := fmt.Sprintf("%s:%s", c.Config.MerchantId, referenceNo)
+
+	fingerprint, err := idempotencyFingerprint(endpointDirectDebitPayment, requestBody, "validUpTo")
+	if err != nil {
+		return nil, err
+	}
+
+	now := c.getCurrentTime()
+	record := IdempotencyRecord{
+		Fingerprint: fingerprint,
+		Status:      IdempotencyStatusPending,
+		ExternalId:  externalId,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	created, err := store.Create(key, record)
+	if err != nil {
+		return nil, err
+	}
+
+	if !created {
+		existing, err := store.Get(key)
+		if err != nil {
+			return nil, err
+		}
+
+		if existing == nil {
+			if created, err = store.Create(key, record); err != nil {
+				return nil, err
+			}
+
+			if !created {
+				return nil, fmt.Errorf("%w: %s", ErrIdempotencyInProgress, referenceNo)
+			}
+		} else {
+			if existing.Fingerprint != fingerprint {
+				return nil, fmt.Errorf("%w: %s", ErrIdempotencyConflict, referenceNo)
+			}
+
+			if existing.Status == IdempotencyStatusCompleted {
+				var result DirectDebitPaymentResponse
+				if err = json.Unmarshal(existing.Response, &result); err != nil {
+					return nil, err
+				}
+
+				return &result, nil
+			}
+
+			if existing.Status == IdempotencyStatusPending && now.Sub(existing.UpdatedAt) < defaultPendingTimeout {
+				return nil, fmt.Errorf("%w: %s", ErrIdempotencyInProgress, referenceNo)
+			}
+
+			c.log(ctx, slog.LevelWarn, "previous payment outcome is unknown, querying payment",
+				slog.String("endpoint", endpointDirectDebitPayment.name),
+				slog.String("referenceNo", referenceNo),
+				slog.String("originalExternalId", existing.ExternalId),
+				slog.String("status", existing.Status),
+			)
+
+			query, err := c.queryPayment(ctx, referenceNo)
+			if err == nil && query.IsSuccess() {
+				return nil, &PaymentRecoveredError{ReferenceNo: referenceNo, Query: query}
+			}
+
+			if !isPaymentNotFound(query, err) {
+				if err == nil {
+					err = fmt.Errorf("query payment returned %s %s", query.ResponseCode, query.ResponseMessage)
+				}
+
+				return nil, fmt.Errorf("outcome of payment %s is unknown: %w", referenceNo, err)
+			}
+
+			swapped, err := store.CompareAndSwap(key, *existing, record)
+			if err != nil {
+				return nil, err
+			}
+
+			if !swapped {
+				return nil, fmt.Errorf("%w: %s", ErrIdempotencyInProgress, referenceNo)
+			}
+		}
+	}
+
+	result, sendErr := send(ctx)
+
+	record.UpdatedAt = c.getCurrentTime()
+
+	var httpErr goutil.HttpResponseError
+
+	switch {
+	case sendErr == nil:
+		response, err := json.Marshal(result)
+		if err != nil {
+			return result, err
+		}
+
+		record.Status = IdempotencyStatusCompleted
+		record.Response = response
+		err = store.Update(key, record)
+	case errors.As(sendErr, &httpErr) && httpErr.Code >= 400 && httpErr.Code < 500 && httpErr.Code != http.StatusTooManyRequests:
+		err = store.Delete(key)
+	default:
+		record.Status = IdempotencyStatusUnknown
+		err = store.Update(key, record)
+	}
+
+	if err != nil {
+		c.log(ctx, slog.LevelError, "error when update idempotency record",
+			slog.String("endpoint", endpointDirectDebitPayment.name),
+			slog.String("referenceNo", referenceNo),
+			errorAttr(err),
+		)
+	}
+
+	return result, sendErr
+}
+
+func isPaymentNotFound(query *QueryPaymentResponse, err error) bool {
+	var httpErr goutil.HttpResponseError
+	if errors.As(err, &httpErr) && httpErr.Code == http.StatusNotFound {
+		return true
+	}
+
+	return err == nil && query != nil && strings.HasPrefix(query.ResponseCode, "404")
+}
+
+func idempotencyFingerprint(ep endpoint, requestBody map[string]interface{}, excludes ...string) (string, error) {
+	fields := make(map[string]interface{}, len(requestBody))
+	for k, v := range requestBody {
+		fields[k] = v
+	}
+
+	for _, k := range excludes {
+		delete(fields, k)
+	}
+
+	body, err := MarshalRequestBody(fields)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(append([]byte(fmt.Sprintf("%s:", ep.name)), body...))
+
+	return hex.EncodeToString(sum[:]), nil
+}
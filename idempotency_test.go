@@ -0,0 +1,227 @@
+package dana
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type idempotencyServer struct {
+	*httptest.Server
+	payments  int32
+	queries   int32
+	payFails  int32
+	queryCode int32
+}
+
+func newIdempotencyServer(t *testing.T) *idempotencyServer {
+	t.Helper()
+
+	s := &idempotencyServer{queryCode: http.StatusNotFound}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, URLQueryPayment) {
+			atomic.AddInt32(&s.queries, 1)
+
+			if code := int(atomic.LoadInt32(&s.queryCode)); code != http.StatusOK {
+				w.WriteHeader(code)
+				_ = json.NewEncoder(w).Encode(map[string]string{"responseCode": "4045501", "responseMessage": "Transaction Not Found"})
+
+				return
+			}
+
+			_ = json.NewEncoder(w).Encode(map[string]string{"responseCode": "2005500", "originalReferenceNo": "dana-1", "latestTransactionStatus": "00"})
+
+			return
+		}
+
+		atomic.AddInt32(&s.payments, 1)
+
+		if atomic.LoadInt32(&s.payFails) > 0 {
+			atomic.AddInt32(&s.payFails, -1)
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]string{"responseCode": "2005400", "referenceNo": "dana-1", "webRedirectUrl": "https://m.dana.id/pay"})
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func newIdempotencyClient(t *testing.T, srv *idempotencyServer) (*Client, *MemoryIdempotencyStore) {
+	t.Helper()
+
+	store := NewMemoryIdempotencyStore(0)
+	c := newTestClient(t, srv.URL).WithIdempotencyStore(store)
+
+	return c, store
+}
+
+func pay(c *Client, amount string) (*DirectDebitPaymentResponse, error) {
+	return c.DirectDebitPayment("IDR", amount, "ref-1", "51051000100000000001", "order", nil, nil, nil, nil)
+}
+
+func TestIdempotencyIdenticalRepeatReturnsStoredResult(t *testing.T) {
+	srv := newIdempotencyServer(t)
+	c, _ := newIdempotencyClient(t, srv)
+
+	first, err := pay(c, "10000.00")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := pay(c, "10000.00")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if srv.payments != 1 {
+		t.Fatalf("got %d payments, want 1", srv.payments)
+	}
+
+	if *second.WebRedirectUrl != *first.WebRedirectUrl {
+		t.Fatalf("got %s, want %s", *second.WebRedirectUrl, *first.WebRedirectUrl)
+	}
+}
+
+func TestIdempotencyConflictingRepeat(t *testing.T) {
+	srv := newIdempotencyServer(t)
+	c, _ := newIdempotencyClient(t, srv)
+
+	if _, err := pay(c, "10000.00"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := pay(c, "20000.00"); !errors.Is(err, ErrIdempotencyConflict) {
+		t.Fatalf("got %v, want ErrIdempotencyConflict", err)
+	}
+
+	if srv.payments != 1 {
+		t.Fatalf("got %d payments, want 1", srv.payments)
+	}
+}
+
+func TestIdempotencyPendingRepeat(t *testing.T) {
+	srv := newIdempotencyServer(t)
+	c, store := newIdempotencyClient(t, srv)
+	srv.payFails = 1
+
+	if _, err := pay(c, "10000.00"); err == nil {
+		t.Fatal("expected first payment to fail")
+	}
+
+	record, _ := store.Get("m1:ref-1")
+	record.Status = IdempotencyStatusPending
+	_ = store.Update("m1:ref-1", *record)
+
+	if _, err := pay(c, "10000.00"); !errors.Is(err, ErrIdempotencyInProgress) {
+		t.Fatalf("got %v, want ErrIdempotencyInProgress", err)
+	}
+
+	if srv.payments != 1 || srv.queries != 0 {
+		t.Fatalf("got %d payments and %d queries, want 1 and 0", srv.payments, srv.queries)
+	}
+}
+
+func TestIdempotencyStalePendingIsQueried(t *testing.T) {
+	srv := newIdempotencyServer(t)
+	c, store := newIdempotencyClient(t, srv)
+	srv.payFails = 1
+	srv.queryCode = http.StatusOK
+
+	if _, err := pay(c, "10000.00"); err == nil {
+		t.Fatal("expected first payment to fail")
+	}
+
+	record, _ := store.Get("m1:ref-1")
+	record.Status = IdempotencyStatusPending
+	record.UpdatedAt = record.UpdatedAt.Add(-defaultPendingTimeout - time.Second)
+	_ = store.Update("m1:ref-1", *record)
+
+	var recovered *PaymentRecoveredError
+	if _, err := pay(c, "10000.00"); !errors.As(err, &recovered) {
+		t.Fatalf("got %v, want PaymentRecoveredError", err)
+	}
+
+	if srv.payments != 1 || srv.queries != 1 {
+		t.Fatalf("got %d payments and %d queries, want 1 and 1", srv.payments, srv.queries)
+	}
+}
+
+func TestMemoryIdempotencyStoreCompareAndSwap(t *testing.T) {
+	store := NewMemoryIdempotencyStore(0)
+	now := time.Now()
+	unknown := IdempotencyRecord{Fingerprint: "f", Status: IdempotencyStatusUnknown, ExternalId: "1", CreatedAt: now, UpdatedAt: now}
+	pending := IdempotencyRecord{Fingerprint: "f", Status: IdempotencyStatusPending, ExternalId: "2", CreatedAt: now, UpdatedAt: now.Add(time.Second)}
+
+	if swapped, _ := store.CompareAndSwap("k", unknown, pending); swapped {
+		t.Fatal("swapped a missing record")
+	}
+
+	_, _ = store.Create("k", unknown)
+
+	if swapped, _ := store.CompareAndSwap("k", unknown, pending); !swapped {
+		t.Fatal("expected the first swap to win")
+	}
+
+	if swapped, _ := store.CompareAndSwap("k", unknown, pending); swapped {
+		t.Fatal("expected the second swap to lose")
+	}
+
+	record, _ := store.Get("k")
+	if record.Status != IdempotencyStatusPending || record.ExternalId != "2" {
+		t.Fatalf("got %s %s, want PENDING 2", record.Status, record.ExternalId)
+	}
+}
+
+func TestIdempotencyUnknownOutcomeRecoveredByQuery(t *testing.T) {
+	srv := newIdempotencyServer(t)
+	c, _ := newIdempotencyClient(t, srv)
+	srv.payFails = 1
+	srv.queryCode = http.StatusOK
+
+	if _, err := pay(c, "10000.00"); err == nil {
+		t.Fatal("expected first payment to fail")
+	}
+
+	_, err := pay(c, "10000.00")
+
+	var recovered *PaymentRecoveredError
+	if !errors.As(err, &recovered) {
+		t.Fatalf("got %v, want PaymentRecoveredError", err)
+	}
+
+	if *recovered.Query.OriginalReferenceNo != "dana-1" {
+		t.Fatalf("got %s, want dana-1", *recovered.Query.OriginalReferenceNo)
+	}
+
+	if srv.payments != 1 {
+		t.Fatalf("got %d payments, want 1", srv.payments)
+	}
+}
+
+func TestIdempotencyUnknownOutcomeNotFoundResends(t *testing.T) {
+	srv := newIdempotencyServer(t)
+	c, _ := newIdempotencyClient(t, srv)
+	srv.payFails = 1
+
+	if _, err := pay(c, "10000.00"); err == nil {
+		t.Fatal("expected first payment to fail")
+	}
+
+	result, err := pay(c, "10000.00")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *result.ReferenceNo != "dana-1" || srv.payments != 2 || srv.queries != 1 {
+		t.Fatalf("got reference %s, %d payments and %d queries", *result.ReferenceNo, srv.payments, srv.queries)
+	}
+}
@@ -0,0 +1,165 @@
+package dana
+
+import (
+	"crypto/rand"
+	"fmt"
+	"github.com/vannleonheart/goutil"
+	"math/big"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultIdLength  = 16
+	crockfordBase32  = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	snowflakeMaxNode = 1023
+)
+
+var snowflakeEpoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+type IDGenerator interface {
+	Generate() string
+}
+
+type NumericIDGenerator struct {
+	Length int
+	Now    func() time.Time
+}
+
+func NewNumericIDGenerator(length int) *NumericIDGenerator {
+	return &NumericIDGenerator{Length: length, Now: defaultNow}
+}
+
+func (g *NumericIDGenerator) Generate() string {
+	length := g.Length
+	if length <= 0 {
+		length = defaultIdLength
+	}
+
+	now := defaultNow
+	if g.Now != nil {
+		now = g.Now
+	}
+
+	return fmt.Sprintf("%s%s", now().Format("20060102"), GenerateRequestId(length, length, goutil.NumCharset))
+}
+
+type ULIDGenerator struct {
+	mutex      sync.Mutex
+	now        func() time.Time
+	lastMs     int64
+	lastRandom [10]byte
+}
+
+func NewULIDGenerator() *ULIDGenerator {
+	return &ULIDGenerator{now: time.Now}
+}
+
+func (g *ULIDGenerator) Generate() string {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	ms := g.now().UnixMilli()
+
+	if ms <= g.lastMs {
+		ms = g.lastMs
+
+		for i := len(g.lastRandom) - 1; i >= 0; i-- {
+			g.lastRandom[i]++
+			if g.lastRandom[i] != 0 {
+				break
+			}
+		}
+	} else {
+		randomBytes(g.lastRandom[:])
+		g.lastMs = ms
+	}
+
+	var id [16]byte
+	for i := 0; i < 6; i++ {
+		id[i] = byte(ms >> (8 * (5 - i)))
+	}
+	copy(id[6:], g.lastRandom[:])
+
+	return encodeULID(id)
+}
+
+type SnowflakeGenerator struct {
+	mutex    sync.Mutex
+	now      func() time.Time
+	node     int64
+	lastMs   int64
+	sequence int64
+}
+
+func NewSnowflakeGenerator(node int64) (*SnowflakeGenerator, error) {
+	if node < 0 || node > snowflakeMaxNode {
+		return nil, fmt.Errorf("snowflake node must be between 0 and %d", snowflakeMaxNode)
+	}
+
+	return &SnowflakeGenerator{now: time.Now, node: node}, nil
+}
+
+func (g *SnowflakeGenerator) Generate() string {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	ms := g.now().Sub(snowflakeEpoch).Milliseconds()
+
+	if ms <= g.lastMs {
+		ms = g.lastMs
+		g.sequence = (g.sequence + 1) & 0xfff
+
+		if g.sequence == 0 {
+			ms++
+		}
+	} else {
+		g.sequence = 0
+	}
+
+	g.lastMs = ms
+
+	return strconv.FormatInt(ms<<22|g.node<<12|g.sequence, 10)
+}
+
+func encodeULID(id [16]byte) string {
+	value := new(big.Int).SetBytes(id[:])
+	base := big.NewInt(32)
+	mod := new(big.Int)
+
+	out := make([]byte, 26)
+	for i := len(out) - 1; i >= 0; i-- {
+		value.DivMod(value, base, mod)
+		out[i] = crockfordBase32[mod.Int64()]
+	}
+
+	return string(out)
+}
+
+func randomBytes(b []byte) {
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Errorf("crypto/rand is unavailable: %w", err))
+	}
+}
+
+func randomInt(n int) int {
+	if n <= 1 {
+		return 0
+	}
+
+	v, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		panic(fmt.Errorf("crypto/rand is unavailable: %w", err))
+	}
+
+	return int(v.Int64())
+}
+
+func (c *Client) getIDGenerator() IDGenerator {
+	if c.idGenerator != nil {
+		return c.idGenerator
+	}
+
+	return &NumericIDGenerator{Length: defaultIdLength, Now: c.getCurrentTime}
+}
@@ -0,0 +1,22 @@
+package dana
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNumericIDGeneratorDefaultsToJakartaDate(t *testing.T) {
+	now := NewNumericIDGenerator(4).Now()
+
+	if _, offset := now.Zone(); offset != 7*60*60 {
+		t.Fatalf("got offset %d, want +07:00", offset)
+	}
+
+	g := &NumericIDGenerator{Length: 4, Now: func() time.Time {
+		return time.Date(2024, 1, 1, 18, 0, 0, 0, time.UTC).In(defaultLocation())
+	}}
+
+	if id := g.Generate(); id[:8] != "20240102" || len(id) != 12 {
+		t.Fatalf("got %s, want a 20240102 prefix and 12 digits", id)
+	}
+}
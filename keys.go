@@ -0,0 +1,160 @@
+package dana
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const defaultKeyBits = 2048
+
+type KeyPair struct {
+	PrivateKey *rsa.PrivateKey
+}
+
+func GenerateKeyPair(bits int) (*KeyPair, error) {
+	if bits <= 0 {
+		bits = defaultKeyBits
+	}
+
+	pk, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KeyPair{PrivateKey: pk}, nil
+}
+
+func (k *KeyPair) PrivateKeyPKCS1() string {
+	return base64.StdEncoding.EncodeToString(x509.MarshalPKCS1PrivateKey(k.PrivateKey))
+}
+
+func (k *KeyPair) PrivateKeyPKCS1PEM() string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(k.PrivateKey)}))
+}
+
+func (k *KeyPair) PrivateKeyPKCS8() (string, error) {
+	keyBytes, err := x509.MarshalPKCS8PrivateKey(k.PrivateKey)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(keyBytes), nil
+}
+
+func (k *KeyPair) PrivateKeyPKCS8PEM() (string, error) {
+	keyBytes, err := x509.MarshalPKCS8PrivateKey(k.PrivateKey)
+	if err != nil {
+		return "", err
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyBytes})), nil
+}
+
+func (k *KeyPair) PublicKey() (string, error) {
+	keyBytes, err := x509.MarshalPKIXPublicKey(&k.PrivateKey.PublicKey)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(keyBytes), nil
+}
+
+func (k *KeyPair) PublicKeyPEM() (string, error) {
+	keyBytes, err := x509.MarshalPKIXPublicKey(&k.PrivateKey.PublicKey)
+	if err != nil {
+		return "", err
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: keyBytes})), nil
+}
+
+func DerivePublicKey(privateKey string) (string, error) {
+	pk, err := ParsePrivateKey([]byte(privateKey))
+	if err != nil {
+		return "", err
+	}
+
+	return (&KeyPair{PrivateKey: pk}).PublicKey()
+}
+
+func LoadPrivateKeyFile(path string) (*rsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParsePrivateKey(data)
+}
+
+func ParsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	keyBytes, err := decodeKey(data)
+	if err != nil {
+		return nil, err
+	}
+
+	pvtKey, err := x509.ParsePKCS1PrivateKey(keyBytes)
+	if err == nil {
+		return pvtKey, nil
+	}
+
+	key, err2 := x509.ParsePKCS8PrivateKey(keyBytes)
+	if err2 == nil {
+		valPvtKey, ok := key.(*rsa.PrivateKey)
+		if ok {
+			return valPvtKey, nil
+		}
+
+		return nil, fmt.Errorf("expected *rsa.PrivateKey, got %T", key)
+	}
+
+	return nil, errors.Join(err, err2)
+}
+
+func ParsePublicKey(data []byte) (*rsa.PublicKey, error) {
+	keyBytes, err := decodeKey(data)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := x509.ParsePKIXPublicKey(keyBytes)
+	if err == nil {
+		valPubKey, ok := key.(*rsa.PublicKey)
+		if ok {
+			return valPubKey, nil
+		}
+
+		return nil, fmt.Errorf("expected *rsa.PublicKey, got %T", key)
+	}
+
+	pubKey, err2 := x509.ParsePKCS1PublicKey(keyBytes)
+	if err2 == nil {
+		return pubKey, nil
+	}
+
+	return nil, errors.Join(err, err2)
+}
+
+func decodeKey(data []byte) ([]byte, error) {
+	trimmed := strings.TrimSpace(string(data))
+	if len(trimmed) == 0 {
+		return nil, fmt.Errorf("key is empty")
+	}
+
+	if strings.HasPrefix(trimmed, "-----BEGIN") {
+		block, _ := pem.Decode([]byte(trimmed))
+		if block == nil {
+			return nil, fmt.Errorf("invalid pem key")
+		}
+
+		return block.Bytes, nil
+	}
+
+	return base64.StdEncoding.DecodeString(strings.Join(strings.Fields(trimmed), ""))
+}
@@ -0,0 +1,60 @@
+package dana
+
+import (
+	"testing"
+)
+
+func TestKeyPairRoundTrip(t *testing.T) {
+	pair := &KeyPair{PrivateKey: testPrivateKey(t)}
+
+	pkcs8, err := pair.PrivateKeyPKCS8()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pkcs8PEM, err := pair.PrivateKeyPKCS8PEM()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, encoded := range map[string]string{
+		"pkcs1":     pair.PrivateKeyPKCS1(),
+		"pkcs1 pem": pair.PrivateKeyPKCS1PEM(),
+		"pkcs8":     pkcs8,
+		"pkcs8 pem": pkcs8PEM,
+	} {
+		pk, err := ParsePrivateKey([]byte(encoded))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		if !pk.Equal(pair.PrivateKey) {
+			t.Fatalf("%s: parsed a different private key", name)
+		}
+	}
+
+	publicKeyPEM, err := pair.PublicKeyPEM()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	derived, err := DerivePublicKey(pair.PrivateKeyPKCS1PEM())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, encoded := range map[string]string{
+		"public key":     testPublicKey(t, pair.PrivateKey),
+		"public key pem": publicKeyPEM,
+		"derived":        derived,
+	} {
+		pub, err := ParsePublicKey([]byte(encoded))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		if !pub.Equal(&pair.PrivateKey.PublicKey) {
+			t.Fatalf("%s: parsed a different public key", name)
+		}
+	}
+}
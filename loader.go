@@ -0,0 +1,218 @@
+package dana
+
+import (
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+func LoadConfigFromFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg Config
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		var raw interface{}
+
+		if err = yaml.Unmarshal(data, &raw); err != nil {
+			return nil, err
+		}
+
+		by, err := json.Marshal(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = json.Unmarshal(by, &cfg); err != nil {
+			return nil, err
+		}
+	default:
+		if err = json.Unmarshal(data, &cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = cfg.Resolve(); err != nil {
+		return &cfg, err
+	}
+
+	return &cfg, nil
+}
+
+func LoadConfigFromEnv(prefix string) (*Config, error) {
+	var cfg Config
+
+	prefix = strings.ToUpper(strings.TrimRight(strings.TrimSpace(prefix), "_"))
+	if len(prefix) > 0 {
+		prefix = fmt.Sprintf("%s_", prefix)
+	}
+
+	if err := loadEnv(reflect.ValueOf(&cfg).Elem(), prefix); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Resolve(); err != nil {
+		return &cfg, err
+	}
+
+	return &cfg, nil
+}
+
+func (cfg *Config) Resolve() error {
+	if len(strings.TrimSpace(cfg.PrivateKeyFile)) > 0 {
+		data, err := os.ReadFile(cfg.PrivateKeyFile)
+		if err != nil {
+			return fmt.Errorf("private_key_file: %w", err)
+		}
+
+		cfg.PrivateKey = string(data)
+	}
+
+	if len(strings.TrimSpace(cfg.PublicKeyFile)) > 0 {
+		data, err := os.ReadFile(cfg.PublicKeyFile)
+		if err != nil {
+			return fmt.Errorf("public_key_file: %w", err)
+		}
+
+		cfg.PublicKey = string(data)
+	}
+
+	if err := cfg.ApplyEnvironment(); err != nil {
+		return err
+	}
+
+	if len(strings.TrimSpace(cfg.Timezone)) == 0 {
+		cfg.Timezone = defaultTimezone
+	}
+
+	if cfg.DefaultExpireTime == nil {
+		expireTime := defaultExpireTime
+		cfg.DefaultExpireTime = &expireTime
+	}
+
+	if len(strings.TrimSpace(cfg.Mcc)) == 0 {
+		cfg.Mcc = defaultMcc
+	}
+
+	return cfg.Validate()
+}
+
+func loadEnv(v reflect.Value, prefix string) error {
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if len(name) == 0 || name == "-" {
+			continue
+		}
+
+		key := fmt.Sprintf("%s%s", prefix, strings.ToUpper(name))
+		fv := v.Field(i)
+
+		if field.Type.Kind() == reflect.Ptr && field.Type.Elem().Kind() == reflect.Struct {
+			nested := reflect.New(field.Type.Elem())
+			if err := loadEnv(nested.Elem(), fmt.Sprintf("%s_", key)); err != nil {
+				return err
+			}
+
+			if !nested.Elem().IsZero() {
+				fv.Set(nested)
+			}
+
+			continue
+		}
+
+		value, exist := os.LookupEnv(key)
+		if !exist {
+			continue
+		}
+
+		if err := setEnvValue(fv, value); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+func setEnvValue(fv reflect.Value, value string) error {
+	if fv.Kind() == reflect.Ptr {
+		ptr := reflect.New(fv.Type().Elem())
+		if err := setEnvValue(ptr.Elem(), value); err != nil {
+			return err
+		}
+
+		fv.Set(ptr)
+
+		return nil
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+
+		fv.SetInt(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+
+		fv.SetFloat(f)
+	case reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); len(item) > 0 {
+				items = append(items, item)
+			}
+		}
+
+		fv.Set(reflect.ValueOf(items))
+	case reflect.Map:
+		if fv.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported type %s", fv.Type())
+		}
+
+		items := map[string]string{}
+		for _, pair := range strings.Split(value, ",") {
+			k, v, found := strings.Cut(pair, "=")
+			if !found {
+				return fmt.Errorf("expected key=value, got %s", pair)
+			}
+
+			items[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+
+		fv.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported type %s", fv.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,151 @@
+package dana
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func setTestEnv(t *testing.T, env map[string]string) {
+	t.Helper()
+
+	env["DANA_ENVIRONMENT"] = EnvironmentSandbox
+	env["DANA_MERCHANT_ID"] = "m1"
+	env["DANA_CLIENT_ID"] = "p1"
+	env["DANA_CLIENT_SECRET"] = "s1"
+	env["DANA_PRIVATE_KEY"] = (&KeyPair{PrivateKey: testPrivateKey(t)}).PrivateKeyPKCS1()
+
+	for k, v := range env {
+		t.Setenv(k, v)
+	}
+}
+
+func TestLoadConfigFromEnv(t *testing.T) {
+	setTestEnv(t, map[string]string{
+		"DANA_DEFAULT_EXPIRE_TIME":              "30",
+		"DANA_SCOPES":                           "PUBLIC_ID, MINI_DANA",
+		"DANA_CHANNEL_IDS":                      "QuickPay=1, QueryPayment=2",
+		"DANA_RETRY_MAX_ATTEMPTS":               "3",
+		"DANA_RATE_LIMIT_REQUESTS_PER_SECOND":   "2.5",
+		"DANA_RATE_LIMIT_FAIL_FAST":             "true",
+		"DANA_CLOCK_SKEW_ENABLE":                "true",
+		"DANA_CIRCUIT_BREAKER_COOLDOWN_SECONDS": "45",
+	})
+
+	cfg, err := LoadConfigFromEnv("dana_")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.ApiUrl != "https://api.sandbox.dana.id" || *cfg.DefaultExpireTime != 30 || cfg.Timezone != defaultTimezone {
+		t.Fatalf("got api url %s, expire time %d, timezone %s", cfg.ApiUrl, *cfg.DefaultExpireTime, cfg.Timezone)
+	}
+
+	if !reflect.DeepEqual(cfg.Scopes, []string{"PUBLIC_ID", "MINI_DANA"}) || !reflect.DeepEqual(cfg.ChannelIds, map[string]string{"QuickPay": "1", "QueryPayment": "2"}) {
+		t.Fatalf("got scopes %v and channel ids %v", cfg.Scopes, cfg.ChannelIds)
+	}
+
+	if cfg.Retry.MaxAttempts != 3 || cfg.RateLimit.RequestsPerSecond != 2.5 || !cfg.RateLimit.FailFast || !cfg.ClockSkew.Enable || cfg.CircuitBreaker.CooldownSeconds != 45 {
+		t.Fatalf("got retry %+v, rate limit %+v, clock skew %+v, circuit breaker %+v", cfg.Retry, cfg.RateLimit, cfg.ClockSkew, cfg.CircuitBreaker)
+	}
+
+	if cfg.Log != nil {
+		t.Fatalf("got log %+v, want nil when no log variable is set", cfg.Log)
+	}
+}
+
+func TestLoadConfigFromEnvRejectsBadValues(t *testing.T) {
+	tests := []struct {
+		key   string
+		value string
+		want  string
+	}{
+		{key: "DANA_RATE_LIMIT_ENDPOINTS", value: "QuickPay=1", want: "DANA_RATE_LIMIT_ENDPOINTS: unsupported type"},
+		{key: "DANA_RATE_LIMIT_BURST", value: "many", want: "DANA_RATE_LIMIT_BURST"},
+		{key: "DANA_RATE_LIMIT_REQUESTS_PER_SECOND", value: "fast", want: "DANA_RATE_LIMIT_REQUESTS_PER_SECOND"},
+		{key: "DANA_CHANNEL_IDS", value: "QuickPay", want: "expected key=value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			setTestEnv(t, map[string]string{tt.key: tt.value})
+
+			if _, err := LoadConfigFromEnv("DANA"); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfigFromFile(t *testing.T) {
+	privateKey := (&KeyPair{PrivateKey: testPrivateKey(t)}).PrivateKeyPKCS1()
+
+	files := map[string]string{
+		"dana.json": `{
+  "environment": "sandbox",
+  "merchant_id": "m1",
+  "client_id": "p1",
+  "client_secret": "s1",
+  "private_key": "` + privateKey + `",
+  "rate_limit": {
+    "requests_per_second": 2.5,
+    "endpoints": {"QuickPay": {"requests_per_second": 0.5, "burst": 2}}
+  },
+  "log": {"enable": true, "redact_fields": ["email"]}
+}`,
+		"dana.yaml": `environment: sandbox
+merchant_id: m1
+client_id: p1
+client_secret: s1
+private_key: ` + privateKey + `
+rate_limit:
+  requests_per_second: 2.5
+  endpoints:
+    QuickPay:
+      requests_per_second: 0.5
+      burst: 2
+log:
+  enable: true
+  redact_fields:
+    - email
+`,
+	}
+
+	var loaded []*Config
+
+	for name, content := range files {
+		path := filepath.Join(t.TempDir(), name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		cfg, err := LoadConfigFromFile(path)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		rule := cfg.RateLimit.Endpoints["QuickPay"]
+		if cfg.RateLimit.RequestsPerSecond != 2.5 || rule.RequestsPerSecond != 0.5 || rule.Burst != 2 || !cfg.Log.Enable {
+			t.Fatalf("%s: got rate limit %+v and log %+v", name, cfg.RateLimit, cfg.Log)
+		}
+
+		loaded = append(loaded, cfg)
+	}
+
+	if !reflect.DeepEqual(loaded[0], loaded[1]) {
+		t.Fatalf("json and yaml loaded different configs:\n%+v\n%+v", loaded[0], loaded[1])
+	}
+}
+
+func TestLoadConfigFromFileRejectsUnknownTypes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dana.yaml")
+	if err := os.WriteFile(path, []byte("rate_limit:\n  burst: many\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadConfigFromFile(path); err == nil {
+		t.Fatal("expected a type error")
+	}
+}
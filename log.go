@@ -0,0 +1,142 @@
+package dana
+
+import (
+	"context"
+	"fmt"
+	"github.com/vannleonheart/goutil"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+)
+
+type Logger interface {
+	LogAttrs(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr)
+}
+
+type FileLogger struct {
+	config LogConfig
+	level  slog.Level
+	clock  func() time.Time
+}
+
+func NewFileLogger(config LogConfig) *FileLogger {
+	level := slog.LevelDebug
+	if len(strings.TrimSpace(config.Level)) > 0 {
+		if err := level.UnmarshalText([]byte(strings.TrimSpace(config.Level))); err != nil {
+			level = slog.LevelDebug
+		}
+	}
+
+	return &FileLogger{config: config, level: level, clock: time.Now}
+}
+
+func (l *FileLogger) LogAttrs(_ context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	if !l.config.Enable || level < l.level {
+		return
+	}
+
+	data := map[string]interface{}{}
+	for _, attr := range attrs {
+		data[attr.Key] = attrValue(attr.Value)
+	}
+
+	entry := map[string]interface{}{
+		"timestamp": l.clock().Format(time.RFC3339),
+		"level":     strings.ToLower(level.String()),
+		"message":   msg,
+		"data":      data,
+	}
+
+	if err := goutil.WriteJsonToFile(entry, l.config.Path, l.config.Filename, l.config.Extension, l.config.Rotation); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "dana: error when write log file: %s\n", err.Error())
+	}
+}
+
+func attrValue(value slog.Value) interface{} {
+	value = value.Resolve()
+
+	if value.Kind() != slog.KindGroup {
+		if err, ok := value.Any().(error); ok {
+			return err.Error()
+		}
+
+		return value.Any()
+	}
+
+	group := map[string]interface{}{}
+	for _, attr := range value.Group() {
+		group[attr.Key] = attrValue(attr.Value)
+	}
+
+	return group
+}
+
+func (l *FileLogger) matches(config LogConfig) bool {
+	return l.config.Enable == config.Enable &&
+		l.config.Level == config.Level &&
+		l.config.Path == config.Path &&
+		l.config.Filename == config.Filename &&
+		l.config.Extension == config.Extension &&
+		l.config.Rotation == config.Rotation
+}
+
+func (c *Client) getLogger() Logger {
+	if c.logger != nil {
+		return c.logger
+	}
+
+	if c.Config.Log == nil || !c.Config.Log.Enable {
+		return nil
+	}
+
+	c.logMutex.Lock()
+	defer c.logMutex.Unlock()
+
+	if c.fileLogger == nil || !c.fileLogger.matches(*c.Config.Log) {
+		c.fileLogger = NewFileLogger(*c.Config.Log)
+		c.fileLogger.clock = func() time.Time {
+			return c.getClock().Now()
+		}
+	}
+
+	return c.fileLogger
+}
+
+func (c *Client) log(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	logger := c.getLogger()
+	if logger == nil {
+		return
+	}
+
+	redactor := c.getRedactor()
+	redacted := make([]slog.Attr, 0, len(attrs))
+	for _, attr := range attrs {
+		redacted = append(redacted, redactor.RedactAttr(attr))
+	}
+
+	logger.LogAttrs(ctx, level, msg, redacted...)
+}
+
+func errorAttr(err error) slog.Attr {
+	if err == nil {
+		return slog.String("error", "")
+	}
+
+	return slog.String("error", err.Error())
+}
+
+func referenceNo(requestBody interface{}) string {
+	body, ok := requestBody.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+
+	for _, key := range []string{"partnerReferenceNo", "originalPartnerReferenceNo", "originalReferenceNo", "partnerRefundNo"} {
+		if value, exist := body[key].(string); exist {
+			return value
+		}
+	}
+
+	return ""
+}
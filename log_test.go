@@ -0,0 +1,37 @@
+package dana
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFileLoggerIsCachedAndUsesClientClock(t *testing.T) {
+	dir := t.TempDir()
+	c := New(Config{Log: &LogConfig{Enable: true, Path: dir, Filename: "dana", Extension: "log"}}).
+		WithClock(FixedClock{Time: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)})
+
+	if c.getLogger() != c.getLogger() {
+		t.Fatal("file logger was rebuilt without a config change")
+	}
+
+	c.log(context.Background(), slog.LevelInfo, "hello")
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("got files %v, err %v", files, err)
+	}
+
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(data), `"timestamp":"2024-01-02T03:04:05Z"`) {
+		t.Fatalf("entry was not stamped with the client clock: %s", data)
+	}
+}
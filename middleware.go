@@ -0,0 +1,211 @@
+package dana
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type Handler func(ctx context.Context, req *Request) (*Response, error)
+
+type Middleware func(next Handler) Handler
+
+type RetryConfig struct {
+	MaxAttempts int   `json:"max_attempts"`
+	BackoffMs   int64 `json:"backoff_ms"`
+}
+
+func (c *Client) Use(middlewares ...Middleware) {
+	c.middlewares = append(c.middlewares, middlewares...)
+}
+
+func (c *Client) WithMiddleware(middlewares ...Middleware) *Client {
+	c.Use(middlewares...)
+
+	return c
+}
+
+func (c *Client) ClearMiddlewares() {
+	c.middlewares = nil
+}
+
+func (c *Client) handler() Handler {
+	chain := []Middleware{c.telemetryMiddleware(), c.loggingMiddleware()}
+	chain = append(chain, c.middlewares...)
+
+	if c.Config.CircuitBreaker != nil && c.Config.CircuitBreaker.Enable {
+		chain = append(chain, c.circuitBreakerMiddleware(*c.Config.CircuitBreaker))
+	}
+
+	if c.Config.Retry != nil && c.Config.Retry.MaxAttempts > 1 {
+		chain = append(chain, c.retryMiddleware(*c.Config.Retry))
+	}
+
+	if limiter := c.getRateLimiter(); limiter != nil {
+		chain = append(chain, c.rateLimitMiddleware(limiter))
+	}
+
+	h := Handler(c.transport)
+	for i := len(chain) - 1; i >= 0; i-- {
+		h = chain[i](h)
+	}
+
+	return h
+}
+
+func (c *Client) telemetryMiddleware() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			if req.dryRun {
+				return next(ctx, req)
+			}
+
+			telemetry := c.getTelemetry()
+			started := time.Now()
+
+			ctx, span := telemetry.start(ctx, req.endpoint, req.ExternalId)
+
+			resp, err := next(ctx, req)
+
+			httpStatus := 0
+			code := ""
+			if resp != nil {
+				httpStatus = resp.StatusCode
+				code = responseCode(resp.Result)
+			}
+
+			telemetry.end(ctx, span, req.endpoint, started, httpStatus, code, err)
+
+			return resp, err
+		}
+	}
+}
+
+func (c *Client) loggingMiddleware() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			started := time.Now()
+
+			resp, err := next(ctx, req)
+
+			attrs := []slog.Attr{
+				slog.String("endpoint", req.Endpoint),
+				slog.String("path", req.Path),
+				slog.String("externalId", req.ExternalId),
+				slog.String("referenceNo", req.ReferenceNo),
+				slog.Duration("latency", time.Since(started)),
+			}
+
+			var result interface{}
+
+			code := ""
+			if resp != nil {
+				result = resp.Result
+				code = responseCode(resp.Result)
+				attrs = append(attrs, slog.String("responseCode", code), slog.Int("httpStatus", resp.StatusCode))
+			}
+
+			payload := []slog.Attr{
+				slog.String("url", req.Url),
+				slog.Any("headers", req.Headers),
+				slog.Any("body", req.RequestBody),
+				slog.Any("result", result),
+			}
+
+			if isDryRun(err) {
+				c.log(ctx, slog.LevelDebug, "dana request prepared in dry run", append(attrs, payload...)...)
+
+				return resp, err
+			}
+
+			if err != nil {
+				c.log(ctx, slog.LevelError, "error when send http request", append(append(attrs, errorAttr(err)), payload...)...)
+
+				return resp, err
+			}
+
+			level := slog.LevelInfo
+			if len(code) > 0 && !strings.HasPrefix(code, "2") {
+				level = slog.LevelWarn
+			}
+
+			c.log(ctx, level, "dana request completed", attrs...)
+			c.log(ctx, slog.LevelDebug, "dana request payload", append(attrs, payload...)...)
+
+			return resp, err
+		}
+	}
+}
+
+func (c *Client) retryMiddleware(config RetryConfig) Middleware {
+	backoff := time.Duration(config.BackoffMs) * time.Millisecond
+	if backoff <= 0 {
+		backoff = 200 * time.Millisecond
+	}
+
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			headers := make(map[string]string, len(req.Headers))
+			for k, v := range req.Headers {
+				headers[k] = v
+			}
+
+			for attempt := 1; ; attempt++ {
+				resp, err := next(ctx, req)
+				if attempt >= config.MaxAttempts || !isRetryable(req.endpoint, resp, err) || ctx.Err() != nil {
+					return resp, err
+				}
+
+				wait := backoff << (attempt - 1)
+				if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+					if after := retryAfter(resp.Header, time.Now()); after > wait {
+						wait = after
+					}
+				}
+
+				c.getTelemetry().recordRetry(ctx, req.endpoint)
+				c.log(ctx, slog.LevelWarn, "retrying dana request",
+					slog.String("endpoint", req.Endpoint),
+					slog.String("externalId", req.ExternalId),
+					slog.Int("attempt", attempt),
+					slog.Duration("wait", wait),
+					errorAttr(err),
+				)
+
+				timer := time.NewTimer(wait)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+
+					return resp, err
+				case <-timer.C:
+				}
+
+				req.Headers = make(map[string]string, len(headers))
+				for k, v := range headers {
+					req.Headers[k] = v
+				}
+			}
+		}
+	}
+}
+
+func isRetryable(ep endpoint, resp *Response, err error) bool {
+	var signErr signatureError
+	if errors.Is(err, context.Canceled) || errors.Is(err, ErrRateLimited) || errors.As(err, &signErr) || isDryRun(err) {
+		return false
+	}
+
+	if !ep.retryable {
+		return resp != nil && resp.StatusCode == http.StatusTooManyRequests
+	}
+
+	if resp == nil {
+		return err != nil
+	}
+
+	return resp.StatusCode >= 500 || resp.StatusCode == 429
+}
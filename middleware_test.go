@@ -0,0 +1,39 @@
+package dana
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestRetryMiddlewareSkipsNonRetryableEndpoints(t *testing.T) {
+	tests := []struct {
+		endpoint endpoint
+		status   int
+		want     int
+	}{
+		{endpoint: endpointQueryPayment, status: http.StatusInternalServerError, want: 3},
+		{endpoint: endpointDirectDebitPayment, status: http.StatusInternalServerError, want: 1},
+		{endpoint: endpointQuickPay, status: http.StatusBadGateway, want: 1},
+		{endpoint: endpointRefund, status: http.StatusServiceUnavailable, want: 1},
+		{endpoint: endpointDirectDebitPayment, status: http.StatusTooManyRequests, want: 3},
+	}
+
+	for _, tt := range tests {
+		c := New(Config{})
+		attempts := 0
+
+		handler := c.retryMiddleware(RetryConfig{MaxAttempts: 3, BackoffMs: 1})(func(ctx context.Context, req *Request) (*Response, error) {
+			attempts++
+
+			return &Response{StatusCode: tt.status}, errors.New(http.StatusText(tt.status))
+		})
+
+		_, _ = handler(context.Background(), &Request{Endpoint: tt.endpoint.name, Headers: map[string]string{}, endpoint: tt.endpoint})
+
+		if attempts != tt.want {
+			t.Fatalf("%s %d: got %d attempts, want %d", tt.endpoint.name, tt.status, attempts, tt.want)
+		}
+	}
+}
@@ -1,19 +1,12 @@
 package dana
 
-import (
-	"fmt"
-	"github.com/vannleonheart/goutil"
-	"net/http"
-)
+import "context"
 
 func (c *Client) DirectDebitPayment(currency, amount, referenceNo, productCode, orderTitle string, mcc *string, expireTime *int64, paymentOptions *[]map[string]interface{}, urlParams *[]map[string]string) (*DirectDebitPaymentResponse, error) {
-	timestamp := c.getTimestamp()
+	ctx := c.getContext()
 	requestId := c.getRequestId(nil)
 
-	currentMcc := defaultMcc
-	if mcc != nil {
-		currentMcc = *mcc
-	}
+	currentMcc := c.getMcc(mcc)
 
 	requestBody := map[string]interface{}{
 		"partnerReferenceNo": referenceNo,
@@ -44,70 +37,27 @@ func (c *Client) DirectDebitPayment(currency, amount, referenceNo, productCode,
 		requestBody["urlParams"] = urlParams
 	}
 
-	encodeRequestBody := EncodeRequestBody(requestBody)
-	strToSign := fmt.Sprintf("%s:%s:%s:%s", http.MethodPost, fmt.Sprintf("/%s", URLDirectDebitPayment), encodeRequestBody, timestamp)
-	signature, err := c.sign(strToSign)
-	if err != nil {
-		c.log("error", map[string]interface{}{
-			"function":     "DirectDebitPayment",
-			"message":      "error when sign request",
-			"error":        err.Error(),
-			"stringToSign": strToSign,
-		})
+	send := func(ctx context.Context) (*DirectDebitPaymentResponse, error) {
+		var result DirectDebitPaymentResponse
 
-		return nil, err
-	}
+		if _, err := c.sendRequest(ctx, endpointDirectDebitPayment, requestBody, requestId, nil, &result, c.isDryRunContext(ctx)); err != nil {
+			return nil, err
+		}
 
-	requestHeaders := map[string]string{
-		"Content-type":  "application/json",
-		"X-TIMESTAMP":   timestamp,
-		"X-PARTNER-ID":  c.Config.ClientId,
-		"X-EXTERNAL-ID": requestId,
-		"X-SIGNATURE":   *signature,
-		"CHANNEL-ID":    c.getChannelId(),
+		return &result, nil
 	}
 
-	requestUrl := fmt.Sprintf("%s/%s", c.Config.ApiUrl, URLDirectDebitPayment)
-
-	var result DirectDebitPaymentResponse
-
-	if _, err = goutil.SendHttpPost(requestUrl, requestBody, &requestHeaders, &result, nil); err != nil {
-		c.log("error", map[string]interface{}{
-			"function": "DirectDebitPayment",
-			"message":  "error when send http post",
-			"error":    err,
-			"url":      requestUrl,
-			"headers":  requestHeaders,
-			"body":     requestBody,
-		})
-
-		return nil, err
+	if c.idempotencyStore != nil && !c.isDryRunContext(ctx) {
+		return c.idempotentDirectDebitPayment(ctx, referenceNo, requestId, requestBody, send)
 	}
 
-	c.log("debug", map[string]interface{}{
-		"function": "DirectDebitPayment",
-		"result":   result,
-		"url":      requestUrl,
-		"headers":  requestHeaders,
-		"body":     requestBody,
-	})
-
-	return &result, nil
+	return send(ctx)
 }
 
 func (c *Client) QuickPay(currency, amount, referenceNo, productCode, orderTitle string, mcc *string, expireTime *int64, paymentOptions *[]map[string]interface{}) (*QuickPayResponse, error) {
-	if err := c.EnsureB2BAccessToken(); err != nil {
-		return nil, err
-	}
-
-	accessToken := c.b2bAccessToken.AccessToken
-	timestamp := c.getTimestamp()
 	requestId := c.getRequestId(nil)
 
-	currentMcc := defaultMcc
-	if mcc != nil {
-		currentMcc = *mcc
-	}
+	currentMcc := c.getMcc(mcc)
 
 	requestBody := map[string]interface{}{
 		"title":              orderTitle,
@@ -132,60 +82,16 @@ func (c *Client) QuickPay(currency, amount, referenceNo, productCode, orderTitle
 		requestBody["payOptionDetails"] = *paymentOptions
 	}
 
-	encodeRequestBody := EncodeRequestBody(requestBody)
-	strToSign := fmt.Sprintf("%s:%s:%s:%s", http.MethodPost, fmt.Sprintf("/%s", URLQuickPay), encodeRequestBody, timestamp)
-	signature, err := c.sign(strToSign)
-	if err != nil {
-		c.log("error", map[string]interface{}{
-			"function":     "QuickPay",
-			"message":      "error when sign request",
-			"error":        err.Error(),
-			"stringToSign": strToSign,
-		})
-
-		return nil, err
-	}
-
-	requestHeaders := map[string]string{
-		"Authorization": fmt.Sprintf("Bearer %s", accessToken),
-		"Content-type":  "application/json",
-		"X-TIMESTAMP":   timestamp,
-		"X-PARTNER-ID":  c.Config.ClientId,
-		"X-EXTERNAL-ID": requestId,
-		"X-SIGNATURE":   *signature,
-		"CHANNEL-ID":    c.getChannelId(),
-	}
-
-	requestUrl := fmt.Sprintf("%s/%s", c.Config.ApiUrl, URLQuickPay)
-
 	var result QuickPayResponse
 
-	if _, err = goutil.SendHttpPost(requestUrl, requestBody, &requestHeaders, &result, nil); err != nil {
-		c.log("error", map[string]interface{}{
-			"function": "QuickPay",
-			"message":  "error when send http post",
-			"error":    err,
-			"url":      requestUrl,
-			"headers":  requestHeaders,
-			"body":     requestBody,
-		})
-
+	if err := c.send(endpointQuickPay, requestBody, requestId, nil, &result); err != nil {
 		return nil, err
 	}
 
-	c.log("debug", map[string]interface{}{
-		"function": "QuickPay",
-		"result":   result,
-		"url":      requestUrl,
-		"headers":  requestHeaders,
-		"body":     requestBody,
-	})
-
 	return &result, nil
 }
 
 func (c *Client) CancelOrder(referenceNo string) (*CancelOrderRequest, error) {
-	timestamp := c.getTimestamp()
 	requestId := c.getRequestId(nil)
 
 	requestBody := map[string]interface{}{
@@ -193,59 +99,20 @@ func (c *Client) CancelOrder(referenceNo string) (*CancelOrderRequest, error) {
 		"originalPartnerReferenceNo": referenceNo,
 	}
 
-	encodeRequestBody := EncodeRequestBody(requestBody)
-	strToSign := fmt.Sprintf("%s:%s:%s:%s", http.MethodPost, fmt.Sprintf("/%s", URLCancelPayment), encodeRequestBody, timestamp)
-	signature, err := c.sign(strToSign)
-	if err != nil {
-		c.log("error", map[string]interface{}{
-			"function":     "CancelPayment",
-			"message":      "error when sign request",
-			"error":        err.Error(),
-			"stringToSign": strToSign,
-		})
-
-		return nil, err
-	}
-
-	requestHeaders := map[string]string{
-		"Content-type":  "application/json",
-		"X-TIMESTAMP":   timestamp,
-		"X-PARTNER-ID":  c.Config.ClientId,
-		"X-EXTERNAL-ID": requestId,
-		"X-SIGNATURE":   *signature,
-		"CHANNEL-ID":    c.getChannelId(),
-	}
-
-	requestUrl := fmt.Sprintf("%s/%s", c.Config.ApiUrl, URLCancelPayment)
-
 	var result CancelOrderRequest
 
-	if _, err = goutil.SendHttpPost(requestUrl, requestBody, &requestHeaders, &result, nil); err != nil {
-		c.log("error", map[string]interface{}{
-			"function": "CancelPayment",
-			"message":  "error when send http post",
-			"error":    err,
-			"url":      requestUrl,
-			"headers":  requestHeaders,
-			"body":     requestBody,
-		})
-
+	if err := c.send(endpointCancelPayment, requestBody, requestId, nil, &result); err != nil {
 		return nil, err
 	}
 
-	c.log("debug", map[string]interface{}{
-		"function": "CancelPayment",
-		"result":   result,
-		"url":      requestUrl,
-		"headers":  requestHeaders,
-		"body":     requestBody,
-	})
-
 	return &result, nil
 }
 
 func (c *Client) QueryPayment(referenceNo string) (*QueryPaymentResponse, error) {
-	timestamp := c.getTimestamp()
+	return c.queryPayment(c.getContext(), referenceNo)
+}
+
+func (c *Client) queryPayment(ctx context.Context, referenceNo string) (*QueryPaymentResponse, error) {
 	requestId := c.getRequestId(nil)
 
 	requestBody := map[string]interface{}{
@@ -254,59 +121,16 @@ func (c *Client) QueryPayment(referenceNo string) (*QueryPaymentResponse, error)
 		"originalPartnerReferenceNo": referenceNo,
 	}
 
-	encodeRequestBody := EncodeRequestBody(requestBody)
-	strToSign := fmt.Sprintf("%s:%s:%s:%s", http.MethodPost, fmt.Sprintf("/%s", URLQueryPayment), encodeRequestBody, timestamp)
-	signature, err := c.sign(strToSign)
-	if err != nil {
-		c.log("error", map[string]interface{}{
-			"function":     "QueryPayment",
-			"message":      "error when sign request",
-			"error":        err.Error(),
-			"stringToSign": strToSign,
-		})
-
-		return nil, err
-	}
-
-	requestHeaders := map[string]string{
-		"Content-type":  "application/json",
-		"X-TIMESTAMP":   timestamp,
-		"X-PARTNER-ID":  c.Config.ClientId,
-		"X-EXTERNAL-ID": requestId,
-		"X-SIGNATURE":   *signature,
-		"CHANNEL-ID":    c.getChannelId(),
-	}
-
-	requestUrl := fmt.Sprintf("%s/%s", c.Config.ApiUrl, URLQueryPayment)
-
 	var result QueryPaymentResponse
 
-	if _, err = goutil.SendHttpPost(requestUrl, requestBody, &requestHeaders, &result, nil); err != nil {
-		c.log("error", map[string]interface{}{
-			"function": "QueryPayment",
-			"message":  "error when send http post",
-			"error":    err,
-			"url":      requestUrl,
-			"headers":  requestHeaders,
-			"body":     requestBody,
-		})
-
+	if _, err := c.sendRequest(ctx, endpointQueryPayment, requestBody, requestId, nil, &result, c.isDryRunContext(ctx)); err != nil {
 		return nil, err
 	}
 
-	c.log("debug", map[string]interface{}{
-		"function": "QueryPayment",
-		"result":   result,
-		"url":      requestUrl,
-		"headers":  requestHeaders,
-		"body":     requestBody,
-	})
-
 	return &result, nil
 }
 
 func (c *Client) GenerateQRIS(currency, amount, referenceNo string) (interface{}, error) {
-	timestamp := c.getTimestamp()
 	requestId := c.getRequestId(nil)
 
 	requestBody := map[string]interface{}{
@@ -325,59 +149,16 @@ func (c *Client) GenerateQRIS(currency, amount, referenceNo string) (interface{}
 		},
 	}
 
-	encodeRequestBody := EncodeRequestBody(requestBody)
-	strToSign := fmt.Sprintf("%s:%s:%s:%s", http.MethodPost, fmt.Sprintf("/%s", URLGenerateQRIS), encodeRequestBody, timestamp)
-	signature, err := c.sign(strToSign)
-	if err != nil {
-		c.log("error", map[string]interface{}{
-			"function":     "GenerateQRIS",
-			"message":      "error when sign request",
-			"error":        err.Error(),
-			"stringToSign": strToSign,
-		})
-
-		return nil, err
-	}
-
-	requestHeaders := map[string]string{
-		"Content-type":  "application/json",
-		"X-TIMESTAMP":   timestamp,
-		"X-PARTNER-ID":  c.Config.ClientId,
-		"X-EXTERNAL-ID": requestId,
-		"X-SIGNATURE":   *signature,
-		"CHANNEL-ID":    c.getChannelId(),
-	}
-
-	requestUrl := fmt.Sprintf("%s/%s", c.Config.ApiUrl, URLGenerateQRIS)
-
 	var result interface{}
 
-	if _, err = goutil.SendHttpPost(requestUrl, requestBody, &requestHeaders, &result, nil); err != nil {
-		c.log("error", map[string]interface{}{
-			"function": "GenerateQRIS",
-			"message":  "error when send http post",
-			"error":    err,
-			"url":      requestUrl,
-			"headers":  requestHeaders,
-			"body":     requestBody,
-		})
-
+	if err := c.send(endpointGenerateQRIS, requestBody, requestId, nil, &result); err != nil {
 		return nil, err
 	}
 
-	c.log("debug", map[string]interface{}{
-		"function": "GenerateQRIS",
-		"result":   result,
-		"url":      requestUrl,
-		"headers":  requestHeaders,
-		"body":     requestBody,
-	})
-
 	return &result, nil
 }
 
 func (c *Client) FinishNotify(danaReferenceNo, referenceNo, amount, latestTransactionStatus, createdTime, finishedTime string) (interface{}, error) {
-	timestamp := c.getTimestamp()
 	requestId := c.getRequestId(nil)
 
 	requestBody := map[string]interface{}{
@@ -393,60 +174,16 @@ func (c *Client) FinishNotify(danaReferenceNo, referenceNo, amount, latestTransa
 		"finishedTime":            finishedTime,
 	}
 
-	encodeRequestBody := EncodeRequestBody(requestBody)
-	strToSign := fmt.Sprintf("%s:%s:%s:%s", http.MethodPost, fmt.Sprintf("/%s", URLFinishNotify), encodeRequestBody, timestamp)
-	signature, err := c.sign(strToSign)
-	if err != nil {
-		c.log("error", map[string]interface{}{
-			"function":     "FinishNotify",
-			"message":      "error when sign request",
-			"error":        err.Error(),
-			"stringToSign": strToSign,
-		})
-
-		return nil, err
-	}
-
-	requestHeaders := map[string]string{
-		"Content-type":  "application/json",
-		"X-TIMESTAMP":   timestamp,
-		"X-PARTNER-ID":  c.Config.ClientId,
-		"X-EXTERNAL-ID": requestId,
-		"X-SIGNATURE":   *signature,
-		"CHANNEL-ID":    c.getChannelId(),
-		"ORIGIN":        c.getOrigin(),
-	}
-
-	requestUrl := fmt.Sprintf("%s/%s", c.Config.ApiUrl, URLFinishNotify)
-
 	var result interface{}
 
-	if _, err = goutil.SendHttpPost(requestUrl, requestBody, &requestHeaders, &result, nil); err != nil {
-		c.log("error", map[string]interface{}{
-			"function": "FinishNotify",
-			"message":  "error when send http post",
-			"error":    err,
-			"url":      requestUrl,
-			"headers":  requestHeaders,
-			"body":     requestBody,
-		})
-
+	if err := c.send(endpointFinishNotify, requestBody, requestId, nil, &result); err != nil {
 		return nil, err
 	}
 
-	c.log("debug", map[string]interface{}{
-		"function": "FinishNotify",
-		"result":   result,
-		"url":      requestUrl,
-		"headers":  requestHeaders,
-		"body":     requestBody,
-	})
-
 	return &result, nil
 }
 
 func (c *Client) RefundOrder(orderId, refundId, currency, amount string) (*RefundOrderResponse, error) {
-	timestamp := c.getTimestamp()
 	requestId := c.getRequestId(nil)
 
 	requestBody := map[string]interface{}{
@@ -459,136 +196,100 @@ func (c *Client) RefundOrder(orderId, refundId, currency, amount string) (*Refun
 		},
 	}
 
-	encodeRequestBody := EncodeRequestBody(requestBody)
-	strToSign := fmt.Sprintf("%s:%s:%s:%s", http.MethodPost, fmt.Sprintf("/%s", URLRefund), encodeRequestBody, timestamp)
-	signature, err := c.sign(strToSign)
-	if err != nil {
-		c.log("error", map[string]interface{}{
-			"function":     "RefundOrder",
-			"message":      "error when sign request",
-			"error":        err.Error(),
-			"stringToSign": strToSign,
-		})
+	var result RefundOrderResponse
 
+	if err := c.send(endpointRefund, requestBody, requestId, nil, &result); err != nil {
 		return nil, err
 	}
 
-	requestHeaders := map[string]string{
-		"Content-type":  "application/json",
-		"X-TIMESTAMP":   timestamp,
-		"X-PARTNER-ID":  c.Config.ClientId,
-		"X-EXTERNAL-ID": requestId,
-		"X-SIGNATURE":   *signature,
-		"CHANNEL-ID":    "95221",
-		"ORIGIN":        c.getOrigin(),
-	}
+	return &result, nil
+}
 
-	requestUrl := fmt.Sprintf("%s/%s", c.Config.ApiUrl, URLRefund)
+func (c *Client) TransactionHistory(request *TransactionHistoryRequest, customerAccessToken *AccessToken) (*string, *TransactionHistoryResponse, error) {
+	ctx := c.getContext()
+	externalId := c.getRequestId(nil)
+	accessToken := c.getCustomerAccessToken(customerAccessToken)
 
-	var result RefundOrderResponse
+	result, err := c.transactionHistory(ctx, request, externalId, accessToken)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	if _, err = goutil.SendHttpPost(requestUrl, requestBody, &requestHeaders, &result, nil); err != nil {
-		c.log("error", map[string]interface{}{
-			"function": "RefundOrder",
-			"message":  "error when send http post",
-			"error":    err,
-			"url":      requestUrl,
-			"headers":  requestHeaders,
-			"body":     requestBody,
-		})
+	return &externalId, result, nil
+}
 
+func (c *Client) transactionHistory(ctx context.Context, request *TransactionHistoryRequest, externalId string, accessToken *AccessToken) (*TransactionHistoryResponse, error) {
+	if err := c.CheckCustomerScopes(accessToken, URLTransactionList); err != nil {
 		return nil, err
 	}
 
-	c.log("debug", map[string]interface{}{
-		"function": "RefundOrder",
-		"result":   result,
-		"url":      requestUrl,
-		"headers":  requestHeaders,
-		"body":     requestBody,
-	})
-
-	return &result, nil
-}
+	if request == nil {
+		request = &TransactionHistoryRequest{}
+	}
 
-func (c *Client) TransactionHistory(fromDateTime, toDateTime *string, customerAccessToken *AccessToken) (*string, *TransactionHistoryResponse, error) {
-	timestamp := c.getTimestamp()
-	externalId := c.getRequestId(nil)
-	accessToken := c.getCustomerAccessToken(customerAccessToken)
+	types := defaultTransactionTypes
+	if request.Types != nil && len(*request.Types) > 0 {
+		types = *request.Types
+	}
 
-	if accessToken == nil {
-		return nil, nil, fmt.Errorf("customer access token is required")
+	statuses := defaultTransactionStatuses
+	if request.Statuses != nil && len(*request.Statuses) > 0 {
+		statuses = *request.Statuses
 	}
 
 	requestBody := map[string]interface{}{
 		"additionalInfo": map[string]interface{}{
-			"types":       []string{"PAYMENT", "REFUND", "OFFLINE_TOPUP", "TOP_UP", "REBATE"},
-			"statuses":    []string{"SUCCESS", "FAILED", "INIT", "PROCESSING", "CLOSED", "REVOKED"},
+			"types":       types,
+			"statuses":    statuses,
 			"accessToken": accessToken.AccessToken,
 		},
 	}
 
-	if fromDateTime != nil {
-		requestBody["fromDateTime"] = *fromDateTime
+	if request.FromDateTime != nil {
+		requestBody["fromDateTime"] = *request.FromDateTime
 	}
 
-	if toDateTime != nil {
-		requestBody["toDateTime"] = *toDateTime
+	if request.ToDateTime != nil {
+		requestBody["toDateTime"] = *request.ToDateTime
 	}
 
-	encodeRequestBody := EncodeRequestBody(requestBody)
-	strToSign := fmt.Sprintf("%s:%s:%s:%s", http.MethodPost, fmt.Sprintf("/%s", URLTransactionList), encodeRequestBody, timestamp)
-	signature, err := c.sign(strToSign)
-	if err != nil {
-		c.log("error", map[string]interface{}{
-			"function":     "GetCustomerTransactionList",
-			"message":      "error when sign request",
-			"error":        err.Error(),
-			"stringToSign": strToSign,
-		})
-
-		return nil, nil, err
+	if request.PageSize != nil {
+		requestBody["pageSize"] = *request.PageSize
 	}
 
-	requestHeaders := map[string]string{
-		"Content-type":           "application/json",
-		"Authorization-Customer": fmt.Sprintf("%s %s", accessToken.TokenType, accessToken.AccessToken),
-		"X-TIMESTAMP":            timestamp,
-		"X-SIGNATURE":            *signature,
-		"ORIGIN":                 c.getOrigin(),
-		"X-PARTNER-ID":           c.Config.ClientId,
-		"X-EXTERNAL-ID":          externalId,
-		"X-IP-ADDRESS":           c.getIpAddress(),
-		"X-DEVICE-ID":            c.getDeviceId(),
-		"X-LATITUDE":             c.getLatitude(),
-		"X-LONGITUDE":            c.getLongitude(),
-		"CHANNEL-ID":             "95221",
+	if request.PageNumber != nil {
+		requestBody["pageNumber"] = *request.PageNumber
 	}
 
-	requestUrl := fmt.Sprintf("%s/%s", c.Config.ApiUrl, URLTransactionList)
-
 	var result TransactionHistoryResponse
 
-	if _, err = goutil.SendHttpPost(requestUrl, requestBody, &requestHeaders, &result, nil); err != nil {
-		c.log("error", map[string]interface{}{
-			"function": "GetCustomerTransactionList",
-			"message":  "error when send http post",
-			"error":    err,
-			"url":      requestUrl,
-			"headers":  requestHeaders,
-			"body":     requestBody,
-		})
+	if _, err := c.sendRequest(ctx, endpointTransactionList, requestBody, externalId, accessToken, &result, c.isDryRunContext(ctx)); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (c *Client) TransactionHistoryDetail(originalReferenceNo string, customerAccessToken *AccessToken) (*string, *TransactionHistoryDetailResponse, error) {
+	externalId := c.getRequestId(nil)
+	accessToken := c.getCustomerAccessToken(customerAccessToken)
 
+	if err := c.CheckCustomerScopes(accessToken, URLTransactionDetail); err != nil {
 		return nil, nil, err
 	}
 
-	c.log("debug", map[string]interface{}{
-		"function": "GetCustomerTransactionList",
-		"result":   result,
-		"url":      requestUrl,
-		"headers":  requestHeaders,
-		"body":     requestBody,
-	})
+	requestBody := map[string]interface{}{
+		"originalReferenceNo": originalReferenceNo,
+		"additionalInfo": map[string]interface{}{
+			"accessToken": accessToken.AccessToken,
+		},
+	}
+
+	var result TransactionHistoryDetailResponse
+
+	if err := c.send(endpointTransactionDetail, requestBody, externalId, accessToken, &result); err != nil {
+		return nil, nil, err
+	}
 
 	return &externalId, &result, nil
 }
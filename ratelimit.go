@@ -0,0 +1,317 @@
+package dana
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrRateLimited = errors.New("dana rate limit exceeded")
+
+type RateLimitConfig struct {
+	RequestsPerSecond float64                  `json:"requests_per_second"`
+	Burst             int                      `json:"burst"`
+	MaxInFlight       int                      `json:"max_in_flight"`
+	FailFast          bool                     `json:"fail_fast"`
+	Endpoints         map[string]RateLimitRule `json:"endpoints"`
+}
+
+type RateLimitRule struct {
+	RequestsPerSecond float64 `json:"requests_per_second"`
+	Burst             int     `json:"burst"`
+	MaxInFlight       int     `json:"max_in_flight"`
+}
+
+type rateLimiter struct {
+	failFast     bool
+	global       *limit
+	endpoints    map[string]*limit
+	mutex        sync.Mutex
+	blockedUntil map[string]time.Time
+}
+
+type limit struct {
+	bucket *tokenBucket
+	slots  chan struct{}
+}
+
+type tokenBucket struct {
+	mutex  sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func (c *Client) SetRateLimit(config *RateLimitConfig) {
+	c.rateLimitMutex.Lock()
+	defer c.rateLimitMutex.Unlock()
+
+	c.Config.RateLimit = config
+	c.rateLimiter = nil
+}
+
+func (c *Client) WithRateLimit(config *RateLimitConfig) *Client {
+	c.SetRateLimit(config)
+
+	return c
+}
+
+func (c *Client) ClearRateLimit() {
+	c.SetRateLimit(nil)
+}
+
+func (c *Client) getRateLimiter() *rateLimiter {
+	c.rateLimitMutex.Lock()
+	defer c.rateLimitMutex.Unlock()
+
+	if c.Config.RateLimit == nil {
+		return nil
+	}
+
+	if c.rateLimiter == nil {
+		c.rateLimiter = newRateLimiter(*c.Config.RateLimit)
+	}
+
+	return c.rateLimiter
+}
+
+func (c *Client) rateLimitMiddleware(limiter *rateLimiter) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			if req.dryRun {
+				return next(ctx, req)
+			}
+
+			release, err := limiter.acquire(ctx, req.Endpoint)
+			if err != nil {
+				c.log(ctx, slog.LevelWarn, "dana request rate limited",
+					slog.String("endpoint", req.Endpoint),
+					slog.String("externalId", req.ExternalId),
+					errorAttr(err),
+				)
+
+				return nil, err
+			}
+
+			resp, err := next(ctx, req)
+
+			release()
+
+			if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+				if wait := retryAfter(resp.Header, time.Now()); wait > 0 {
+					limiter.block(req.Endpoint, time.Now().Add(wait))
+				}
+			}
+
+			return resp, err
+		}
+	}
+}
+
+func newRateLimiter(config RateLimitConfig) *rateLimiter {
+	limiter := &rateLimiter{
+		failFast:     config.FailFast,
+		global:       newLimit(config.RequestsPerSecond, config.Burst, config.MaxInFlight),
+		endpoints:    map[string]*limit{},
+		blockedUntil: map[string]time.Time{},
+	}
+
+	for name, rule := range config.Endpoints {
+		limiter.endpoints[name] = newLimit(rule.RequestsPerSecond, rule.Burst, rule.MaxInFlight)
+	}
+
+	return limiter
+}
+
+func newLimit(rps float64, burst, maxInFlight int) *limit {
+	l := &limit{}
+
+	if rps > 0 {
+		if burst <= 0 {
+			burst = 1
+		}
+
+		l.bucket = &tokenBucket{
+			rate:   rps,
+			burst:  float64(burst),
+			tokens: float64(burst),
+			last:   time.Now(),
+		}
+	}
+
+	if maxInFlight > 0 {
+		l.slots = make(chan struct{}, maxInFlight)
+	}
+
+	return l
+}
+
+func (r *rateLimiter) acquire(ctx context.Context, endpoint string) (func(), error) {
+	limits := []*limit{r.global}
+	if l, ok := r.endpoints[endpoint]; ok {
+		limits = append(limits, l)
+	}
+
+	if err := r.wait(ctx, r.blocked(endpoint).Sub(time.Now())); err != nil {
+		return nil, err
+	}
+
+	var reserved []*tokenBucket
+	var wait time.Duration
+
+	cancel := func() {
+		for _, bucket := range reserved {
+			bucket.cancel()
+		}
+	}
+
+	for _, l := range limits {
+		if l.bucket == nil {
+			continue
+		}
+
+		if w := l.bucket.reserve(time.Now()); w > wait {
+			wait = w
+		}
+
+		reserved = append(reserved, l.bucket)
+	}
+
+	if err := r.wait(ctx, wait); err != nil {
+		cancel()
+
+		return nil, err
+	}
+
+	var acquired []chan struct{}
+	release := func() {
+		for _, slots := range acquired {
+			<-slots
+		}
+	}
+
+	for _, l := range limits {
+		if l.slots == nil {
+			continue
+		}
+
+		if r.failFast {
+			select {
+			case l.slots <- struct{}{}:
+			default:
+				release()
+				cancel()
+
+				return nil, fmt.Errorf("%w: too many requests in flight", ErrRateLimited)
+			}
+		} else {
+			select {
+			case l.slots <- struct{}{}:
+			case <-ctx.Done():
+				release()
+				cancel()
+
+				return nil, ctx.Err()
+			}
+		}
+
+		acquired = append(acquired, l.slots)
+	}
+
+	return release, nil
+}
+
+func (r *rateLimiter) wait(ctx context.Context, wait time.Duration) error {
+	if wait <= 0 {
+		return nil
+	}
+
+	if r.failFast {
+		return fmt.Errorf("%w: retry in %s", ErrRateLimited, wait)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+		return fmt.Errorf("%w: retry in %s exceeds context deadline", ErrRateLimited, wait)
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (r *rateLimiter) block(endpoint string, until time.Time) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if until.After(r.blockedUntil[endpoint]) {
+		r.blockedUntil[endpoint] = until
+	}
+}
+
+func (r *rateLimiter) blocked(endpoint string) time.Time {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.blockedUntil[endpoint]
+}
+
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens += elapsed * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+
+		b.last = now
+	}
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+func (b *tokenBucket) cancel() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.tokens++
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+}
+
+func retryAfter(header http.Header, now time.Time) time.Duration {
+	value := strings.TrimSpace(header.Get("Retry-After"))
+	if len(value) == 0 {
+		return 0
+	}
+
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		return at.Sub(now)
+	}
+
+	return 0
+}
@@ -0,0 +1,150 @@
+package dana
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterRejectionKeepsGlobalCapacity(t *testing.T) {
+	r := newRateLimiter(RateLimitConfig{
+		RequestsPerSecond: 0.001,
+		Burst:             2,
+		FailFast:          true,
+		Endpoints: map[string]RateLimitRule{
+			"QuickPay": {RequestsPerSecond: 0.001, Burst: 1},
+		},
+	})
+
+	release, err := r.acquire(context.Background(), "QuickPay")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	release()
+
+	if _, err = r.acquire(context.Background(), "QuickPay"); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("got %v, want ErrRateLimited", err)
+	}
+
+	if _, err = r.acquire(context.Background(), "QueryPayment"); err != nil {
+		t.Fatalf("global token was lost by the rejected call: %v", err)
+	}
+}
+
+func TestTokenBucketWait(t *testing.T) {
+	now := time.Now()
+	b := &tokenBucket{rate: 10, burst: 1, tokens: 1, last: now}
+
+	if wait := b.reserve(now); wait != 0 {
+		t.Fatalf("got wait %s, want 0", wait)
+	}
+
+	if wait := b.reserve(now); wait != 100*time.Millisecond {
+		t.Fatalf("got wait %s, want 100ms", wait)
+	}
+
+	b.cancel()
+
+	if wait := b.reserve(now.Add(100 * time.Millisecond)); wait != 0 {
+		t.Fatalf("got wait %s after refill, want 0", wait)
+	}
+}
+
+func TestRateLimiterDeadlineTooShort(t *testing.T) {
+	r := newRateLimiter(RateLimitConfig{RequestsPerSecond: 1, Burst: 1})
+
+	if _, err := r.acquire(context.Background(), "QuickPay"); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := r.acquire(ctx, "QuickPay"); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("got %v, want ErrRateLimited", err)
+	}
+}
+
+func TestRateLimiterMaxInFlight(t *testing.T) {
+	r := newRateLimiter(RateLimitConfig{MaxInFlight: 1, FailFast: true})
+
+	release, err := r.acquire(context.Background(), "QuickPay")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = r.acquire(context.Background(), "QuickPay"); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("got %v, want ErrRateLimited", err)
+	}
+
+	release()
+
+	if _, err = r.acquire(context.Background(), "QuickPay"); err != nil {
+		t.Fatalf("slot was not released: %v", err)
+	}
+}
+
+func TestRateLimiterMaxInFlightBlocksUntilContextDone(t *testing.T) {
+	r := newRateLimiter(RateLimitConfig{MaxInFlight: 1})
+
+	if _, err := r.acquire(context.Background(), "QuickPay"); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := r.acquire(ctx, "QuickPay"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestRateLimitMiddlewareHonorsRetryAfter(t *testing.T) {
+	c := New(Config{})
+	limiter := newRateLimiter(RateLimitConfig{FailFast: true})
+
+	handler := c.rateLimitMiddleware(limiter)(func(ctx context.Context, req *Request) (*Response, error) {
+		header := http.Header{}
+		header.Set("Retry-After", "30")
+
+		return &Response{StatusCode: http.StatusTooManyRequests, Header: header}, nil
+	})
+
+	if _, err := handler(context.Background(), &Request{Endpoint: "QuickPay"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := handler(context.Background(), &Request{Endpoint: "QuickPay"}); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("got %v, want ErrRateLimited", err)
+	}
+
+	if _, err := handler(context.Background(), &Request{Endpoint: "QueryPayment"}); err != nil {
+		t.Fatalf("other endpoint was blocked: %v", err)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{value: "", want: 0},
+		{value: "5", want: 5 * time.Second},
+		{value: now.Add(time.Minute).Format(http.TimeFormat), want: time.Minute},
+		{value: "soon", want: 0},
+	}
+
+	for _, tt := range tests {
+		header := http.Header{}
+		header.Set("Retry-After", tt.value)
+
+		if got := retryAfter(header, now); got != tt.want {
+			t.Fatalf("retryAfter(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
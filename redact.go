@@ -0,0 +1,168 @@
+package dana
+
+import (
+	"encoding/json"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+const redactedValue = "[REDACTED]"
+
+var defaultRedactFields = []string{
+	"Authorization",
+	"Authorization-Customer",
+	"X-SIGNATURE",
+	"accessToken",
+	"refreshToken",
+	"authCode",
+	"clientSecret",
+	"privateKey",
+	"cardToken",
+	"bankCardToken",
+	"stringToSign",
+	"signature",
+	"phoneNo",
+	"mobileNo",
+	"faxNo",
+	"email",
+	"firstName",
+	"lastName",
+	"address1",
+	"address2",
+	"zipCode",
+	"cityName",
+	"areaName",
+	"stateName",
+	"value",
+}
+
+type Redactor struct {
+	fields map[string]bool
+	allow  map[string]bool
+}
+
+func NewRedactor(fields, allowFields []string) *Redactor {
+	r := &Redactor{
+		fields: map[string]bool{},
+		allow:  map[string]bool{},
+	}
+
+	for _, field := range fields {
+		r.fields[normalizeField(field)] = true
+	}
+
+	for _, field := range allowFields {
+		r.allow[normalizeField(field)] = true
+	}
+
+	return r
+}
+
+func DefaultRedactor() *Redactor {
+	return NewRedactor(defaultRedactFields, nil)
+}
+
+func (r *Redactor) IsRedacted(field string) bool {
+	key := normalizeField(field)
+
+	return r.fields[key] && !r.allow[key]
+}
+
+func (r *Redactor) RedactAttr(attr slog.Attr) slog.Attr {
+	if r.IsRedacted(attr.Key) {
+		return slog.String(attr.Key, redactedValue)
+	}
+
+	value := attr.Value.Resolve()
+
+	switch value.Kind() {
+	case slog.KindGroup:
+		attrs := make([]slog.Attr, 0, len(value.Group()))
+		for _, a := range value.Group() {
+			attrs = append(attrs, r.RedactAttr(a))
+		}
+
+		return slog.Attr{Key: attr.Key, Value: slog.GroupValue(attrs...)}
+	case slog.KindAny:
+		if _, ok := value.Any().(error); ok {
+			return attr
+		}
+
+		return slog.Any(attr.Key, r.Redact(value.Any()))
+	}
+
+	return attr
+}
+
+func (r *Redactor) Redact(data interface{}) interface{} {
+	if data == nil {
+		return nil
+	}
+
+	by, err := json.Marshal(data)
+	if err != nil {
+		return redactedValue
+	}
+
+	var generic interface{}
+
+	if err = json.Unmarshal(by, &generic); err != nil {
+		return redactedValue
+	}
+
+	return r.walk(generic)
+}
+
+func (r *Redactor) walk(data interface{}) interface{} {
+	switch value := data.(type) {
+	case map[string]interface{}:
+		for k, v := range value {
+			if r.IsRedacted(k) {
+				value[k] = redactedValue
+
+				continue
+			}
+
+			value[k] = r.walk(v)
+		}
+
+		return value
+	case []interface{}:
+		for i, v := range value {
+			value[i] = r.walk(v)
+		}
+
+		return value
+	}
+
+	return data
+}
+
+func normalizeField(field string) string {
+	return strings.NewReplacer("-", "", "_", "").Replace(strings.ToLower(strings.TrimSpace(field)))
+}
+
+func (c *Client) getRedactor() *Redactor {
+	fields := defaultRedactFields
+	var allowFields []string
+
+	if c.Config.Log != nil {
+		if c.Config.Log.RedactFields != nil {
+			fields = c.Config.Log.RedactFields
+		}
+
+		allowFields = c.Config.Log.AllowFields
+	}
+
+	c.redactMutex.Lock()
+	defer c.redactMutex.Unlock()
+
+	if c.redactor == nil || !slices.Equal(c.redactFields, fields) || !slices.Equal(c.allowFields, allowFields) {
+		c.redactor = NewRedactor(fields, allowFields)
+		c.redactFields = slices.Clone(fields)
+		c.allowFields = slices.Clone(allowFields)
+	}
+
+	return c.redactor
+}
@@ -0,0 +1,43 @@
+package dana
+
+import (
+	"testing"
+)
+
+func TestDefaultRedactorHidesAddressAndTokenValues(t *testing.T) {
+	redacted := DefaultRedactor().Redact(map[string]interface{}{
+		"resourceType": "OTT",
+		"value":        "one-time-token",
+		"address": map[string]string{
+			"cityName":  "Jakarta Selatan",
+			"areaName":  "Kebayoran Baru",
+			"stateName": "DKI Jakarta",
+		},
+	}).(map[string]interface{})
+
+	if redacted["value"] != redactedValue || redacted["resourceType"] != "OTT" {
+		t.Fatalf("got %v", redacted)
+	}
+
+	for k, v := range redacted["address"].(map[string]interface{}) {
+		if v != redactedValue {
+			t.Fatalf("%s was not redacted: %v", k, v)
+		}
+	}
+}
+
+func TestGetRedactorIsCachedUntilConfigChanges(t *testing.T) {
+	c := New(Config{Log: &LogConfig{}})
+
+	first := c.getRedactor()
+	if c.getRedactor() != first {
+		t.Fatal("redactor was rebuilt without a config change")
+	}
+
+	c.Config.Log.AllowFields = []string{"email"}
+
+	second := c.getRedactor()
+	if second == first || second.IsRedacted("email") {
+		t.Fatal("redactor was not rebuilt after allow fields changed")
+	}
+}
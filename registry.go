@@ -0,0 +1,181 @@
+package dana
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type Registry struct {
+	mutex    sync.RWMutex
+	clients  map[string]*Client
+	partners map[string]string
+	setup    func(client *Client)
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		clients:  map[string]*Client{},
+		partners: map[string]string{},
+	}
+}
+
+func (r *Registry) SetSetup(setup func(client *Client)) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.setup = setup
+}
+
+func (r *Registry) WithSetup(setup func(client *Client)) *Registry {
+	r.SetSetup(setup)
+
+	return r
+}
+
+func (r *Registry) Add(config Config) (*Client, error) {
+	client := New(config)
+
+	if err := r.AddClient(client); err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func (r *Registry) AddClient(client *Client) error {
+	if err := client.Validate(); err != nil {
+		return err
+	}
+
+	r.mutex.RLock()
+	setup := r.setup
+	err := r.checkPartner(client)
+	r.mutex.RUnlock()
+
+	if err != nil {
+		return err
+	}
+
+	if setup != nil {
+		setup(client)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err = r.checkPartner(client); err != nil {
+		return err
+	}
+
+	merchantId := client.Config.MerchantId
+
+	if existing, exist := r.clients[merchantId]; exist {
+		delete(r.partners, existing.Config.ClientId)
+	}
+
+	r.clients[merchantId] = client
+	r.partners[client.Config.ClientId] = merchantId
+
+	return nil
+}
+
+func (r *Registry) checkPartner(client *Client) error {
+	if owner, exist := r.partners[client.Config.ClientId]; exist && owner != client.Config.MerchantId {
+		return fmt.Errorf("client id %s is already registered for merchant %s", client.Config.ClientId, owner)
+	}
+
+	return nil
+}
+
+func (r *Registry) Remove(merchantId string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if client, exist := r.clients[merchantId]; exist {
+		delete(r.partners, client.Config.ClientId)
+		delete(r.clients, merchantId)
+	}
+}
+
+func (r *Registry) Get(merchantId string) (*Client, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	client, exist := r.clients[merchantId]
+	if !exist {
+		return nil, fmt.Errorf("merchant %s is not registered", merchantId)
+	}
+
+	return client, nil
+}
+
+func (r *Registry) GetByPartnerId(partnerId string) (*Client, error) {
+	r.mutex.RLock()
+	merchantId, exist := r.partners[partnerId]
+	r.mutex.RUnlock()
+
+	if !exist {
+		return nil, fmt.Errorf("partner %s is not registered", partnerId)
+	}
+
+	return r.Get(merchantId)
+}
+
+func (r *Registry) Route(merchantId, partnerId string) (*Client, error) {
+	if len(strings.TrimSpace(merchantId)) > 0 {
+		return r.Get(merchantId)
+	}
+
+	if len(strings.TrimSpace(partnerId)) > 0 {
+		return r.GetByPartnerId(partnerId)
+	}
+
+	return nil, fmt.Errorf("merchant id or partner id is required")
+}
+
+func (r *Registry) MerchantIds() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	merchantIds := make([]string, 0, len(r.clients))
+	for merchantId := range r.clients {
+		merchantIds = append(merchantIds, merchantId)
+	}
+
+	sort.Strings(merchantIds)
+
+	return merchantIds
+}
+
+func (r *Registry) ParseNotification(req *http.Request) (*Client, *NotificationRequest, error) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var target struct {
+		MerchantId string `json:"merchantId"`
+	}
+
+	_ = json.Unmarshal(body, &target)
+
+	client, err := r.Route(target.MerchantId, req.Header.Get("X-PARTNER-ID"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	notification, err := client.ParseNotification(req)
+	if err != nil {
+		return client, nil, err
+	}
+
+	return client, notification, nil
+}
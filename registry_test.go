@@ -0,0 +1,52 @@
+package dana
+
+import (
+	"testing"
+)
+
+func newRegistryClient(t *testing.T, merchantId, clientId string) *Client {
+	t.Helper()
+
+	c := newTestClient(t, "https://api.sandbox.dana.id")
+	c.Config.WebUrl = "https://m.sandbox.dana.id"
+	c.Config.MerchantId = merchantId
+	c.Config.ClientId = clientId
+
+	return c
+}
+
+func TestRegistryAddClientConflictKeepsExistingPartner(t *testing.T) {
+	r := NewRegistry()
+
+	if err := r.AddClient(newRegistryClient(t, "m1", "p1")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.AddClient(newRegistryClient(t, "m2", "p2")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.AddClient(newRegistryClient(t, "m1", "p2")); err == nil {
+		t.Fatal("expected client id conflict")
+	}
+
+	client, err := r.GetByPartnerId("p1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if client.Config.MerchantId != "m1" {
+		t.Fatalf("got merchant %s, want m1", client.Config.MerchantId)
+	}
+}
+
+func TestRegistrySetupCanUseRegistry(t *testing.T) {
+	r := NewRegistry()
+	r.SetSetup(func(client *Client) {
+		_, _ = r.Get(client.Config.MerchantId)
+	})
+
+	if err := r.AddClient(newRegistryClient(t, "m1", "p1")); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,282 @@
+package dana
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/vannleonheart/goutil"
+	"io"
+	"log/slog"
+	"net/http"
+)
+
+type Request struct {
+	Endpoint     string
+	Method       string
+	Path         string
+	Url          string
+	Headers      map[string]string
+	Body         []byte
+	StringToSign string
+	ExternalId   string
+	ReferenceNo  string
+	RequestBody  interface{}
+	endpoint     endpoint
+	accessToken  *AccessToken
+	result       interface{}
+	dryRun       bool
+	presigned    bool
+}
+
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	Result     interface{}
+}
+
+func (c *Client) send(ep endpoint, requestBody interface{}, externalId string, customerAccessToken *AccessToken, result interface{}) error {
+	ctx := c.getContext()
+	_, err := c.sendRequest(ctx, ep, requestBody, externalId, customerAccessToken, result, c.isDryRunContext(ctx))
+
+	return err
+}
+
+func (c *Client) sendRequest(ctx context.Context, ep endpoint, requestBody interface{}, externalId string, customerAccessToken *AccessToken, result interface{}, dryRun bool) (*Response, error) {
+	if c.configErr != nil {
+		return nil, c.configErr
+	}
+
+	var accessToken *AccessToken
+
+	switch ep.authType {
+	case authTypeB2B:
+		if dryRun && c.b2bAccessToken == nil {
+			accessToken = &AccessToken{AccessToken: dryRunAccessToken, TokenType: "Bearer"}
+
+			break
+		}
+
+		if err := c.ensureB2BAccessToken(ctx); err != nil {
+			return nil, err
+		}
+
+		accessToken = c.b2bAccessToken
+	case authTypeB2B2C:
+		if customerAccessToken == nil {
+			return nil, fmt.Errorf("customer access token is required")
+		}
+
+		accessToken = customerAccessToken
+	}
+
+	body, err := MarshalRequestBody(requestBody)
+	if err != nil {
+		c.log(ctx, slog.LevelError, "error when marshal request body",
+			slog.String("endpoint", ep.name),
+			slog.String("externalId", externalId),
+			errorAttr(err),
+		)
+
+		return nil, err
+	}
+
+	req := &Request{
+		Endpoint:    ep.name,
+		Method:      ep.method,
+		Path:        ep.path,
+		Url:         fmt.Sprintf("%s/%s", c.Config.ApiUrl, ep.path),
+		Headers:     c.getRequestHeaders(ep, externalId, accessToken),
+		Body:        body,
+		ExternalId:  externalId,
+		ReferenceNo: referenceNo(requestBody),
+		RequestBody: requestBody,
+		endpoint:    ep,
+		accessToken: accessToken,
+		result:      result,
+		dryRun:      dryRun,
+	}
+
+	return c.execute(ctx, req)
+}
+
+func (c *Client) execute(ctx context.Context, req *Request) (*Response, error) {
+	resp, err := c.handler()(ctx, req)
+
+	if resp != nil && resp.Result != req.result && req.result != nil && len(resp.Body) > 0 {
+		if decodeErr := json.Unmarshal(resp.Body, req.result); decodeErr != nil && err == nil {
+			err = decodeErr
+		}
+	}
+
+	return resp, err
+}
+
+func (c *Client) signRequest(req *Request) error {
+	timestamp := c.getTimestamp()
+	req.StringToSign = c.getStringToSign(req.endpoint, HashRequestBody(req.Body), timestamp, req.accessToken)
+
+	signature, err := c.sign(req.endpoint, req.StringToSign)
+	if err != nil {
+		return err
+	}
+
+	req.Headers["X-TIMESTAMP"] = timestamp
+	req.Headers["X-SIGNATURE"] = signature
+
+	return nil
+}
+
+func (c *Client) transport(ctx context.Context, req *Request) (*Response, error) {
+	if req.presigned {
+		return c.sendSignedRequest(ctx, req)
+	}
+
+	if err := c.signRequest(req); err != nil {
+		c.log(ctx, slog.LevelError, "error when sign request",
+			slog.String("endpoint", req.Endpoint),
+			slog.String("externalId", req.ExternalId),
+			errorAttr(err),
+			slog.String("stringToSign", req.StringToSign),
+		)
+
+		return nil, signatureError{err: err}
+	}
+
+	if req.dryRun {
+		return nil, &DryRunError{Request: newPreparedRequest(c, req)}
+	}
+
+	return c.sendSignedRequest(ctx, req)
+}
+
+func (c *Client) sendSignedRequest(ctx context.Context, req *Request) (*Response, error) {
+	started := c.getClock().Now()
+
+	resp, err := c.sendHttpRequest(ctx, req.Method, req.Url, req.Body, req.Headers, req.result)
+	if resp != nil {
+		c.estimateClockSkew(ctx, req.Endpoint, started, c.getClock().Now(), resp.Header)
+	}
+
+	return resp, err
+}
+
+func (c *Client) sendHttpRequest(ctx context.Context, method, requestUrl string, body []byte, headers map[string]string, result interface{}) (*Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, requestUrl, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := c.getHttpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+
+	byteBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &Response{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       byteBody,
+	}
+
+	decoded := result != nil && json.Unmarshal(byteBody, result) == nil
+	if decoded {
+		response.Result = result
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		e := goutil.HttpResponseError{
+			Code:            resp.StatusCode,
+			Message:         resp.Status,
+			ResponseBodyRaw: &byteBody,
+		}
+
+		if decoded {
+			e.ResponseBody = result
+		}
+
+		return response, e
+	}
+
+	return response, nil
+}
+
+type signatureError struct {
+	err error
+}
+
+func (e signatureError) Error() string {
+	return e.err.Error()
+}
+
+func (e signatureError) Unwrap() error {
+	return e.err
+}
+
+func responseCode(result interface{}) string {
+	if coder, ok := result.(interface{ GetResponseCode() string }); ok {
+		return coder.GetResponseCode()
+	}
+
+	return ""
+}
+
+func (c *Client) getStringToSign(ep endpoint, bodyHash, timestamp string, accessToken *AccessToken) string {
+	if ep.authType == authTypeAccessToken {
+		return fmt.Sprintf("%s|%s", c.Config.ClientId, timestamp)
+	}
+
+	token := ""
+	if accessToken != nil {
+		token = accessToken.AccessToken
+	}
+
+	return buildStringToSign(ep.method, fmt.Sprintf("/%s", ep.path), token, bodyHash, timestamp, ep.signature == signatureSymmetric)
+}
+
+func (c *Client) getRequestHeaders(ep endpoint, externalId string, accessToken *AccessToken) map[string]string {
+	requestHeaders := map[string]string{
+		"Content-type": "application/json",
+	}
+
+	if ep.authType == authTypeAccessToken {
+		requestHeaders["X-CLIENT-KEY"] = c.Config.ClientId
+
+		return requestHeaders
+	}
+
+	requestHeaders["X-PARTNER-ID"] = c.Config.ClientId
+	requestHeaders["X-EXTERNAL-ID"] = externalId
+	requestHeaders["CHANNEL-ID"] = c.getChannelId(ep)
+
+	if ep.origin {
+		requestHeaders["ORIGIN"] = c.getOrigin()
+	}
+
+	switch ep.authType {
+	case authTypeB2B:
+		requestHeaders["Authorization"] = fmt.Sprintf("Bearer %s", accessToken.AccessToken)
+	case authTypeB2B2C:
+		requestHeaders["Authorization-Customer"] = fmt.Sprintf("%s %s", accessToken.TokenType, accessToken.AccessToken)
+		requestHeaders["ORIGIN"] = c.getOrigin()
+		requestHeaders["X-IP-ADDRESS"] = c.getIpAddress()
+		requestHeaders["X-DEVICE-ID"] = c.getDeviceId()
+		requestHeaders["X-LATITUDE"] = c.getLatitude()
+		requestHeaders["X-LONGITUDE"] = c.getLongitude()
+	}
+
+	return requestHeaders
+}
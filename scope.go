@@ -0,0 +1,78 @@
+package dana
+
+import (
+	"fmt"
+	"strings"
+)
+
+func MergeScopes(scopes ...[]string) []string {
+	seen := map[string]bool{}
+	result := make([]string, 0)
+
+	for _, list := range scopes {
+		for _, scope := range list {
+			scope = strings.ToUpper(strings.TrimSpace(scope))
+			if len(scope) == 0 || seen[scope] {
+				continue
+			}
+
+			seen[scope] = true
+			result = append(result, scope)
+		}
+	}
+
+	return result
+}
+
+func RequiredCustomerScopes(endpoint string) []string {
+	return customerScopes[endpoint]
+}
+
+func (t *AccessToken) HasScopes(scopes ...string) bool {
+	if t == nil {
+		return false
+	}
+
+	granted := map[string]bool{}
+	for _, scope := range MergeScopes(t.Scopes) {
+		granted[scope] = true
+	}
+
+	for _, scope := range MergeScopes(scopes) {
+		if !granted[scope] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (c *Client) CheckCustomerScopes(accessToken *AccessToken, endpoint string) error {
+	if accessToken == nil {
+		return fmt.Errorf("customer access token is required")
+	}
+
+	if accessToken.Scopes == nil {
+		return nil
+	}
+
+	required := RequiredCustomerScopes(endpoint)
+	if !accessToken.HasScopes(required...) {
+		return fmt.Errorf("customer access token does not grant scopes %s required by %s", strings.Join(required, ","), endpoint)
+	}
+
+	return nil
+}
+
+func (c *Client) getScopes(scopes *[]string) []string {
+	var requested []string
+	if scopes != nil {
+		requested = *scopes
+	}
+
+	if c.Config.DisableDefaultScopes {
+		return MergeScopes(c.Config.Scopes, requested)
+	}
+
+	return MergeScopes(defaultScopes, c.Config.Scopes, requested)
+}
@@ -0,0 +1,39 @@
+package dana
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCustomerApplyTokenRecordsRequestedScopes(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"responseCode": "2007400", "accessToken": "customer-token", "tokenType": "Bearer"})
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+	c.Config.DisableDefaultScopes = true
+	c.Config.Scopes = []string{ScopePublicId, ScopeMiniDana}
+
+	result, err := c.CustomerApplyToken("auth-code", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := result.AccessToken
+	if !token.HasScopes(ScopePublicId, ScopeMiniDana) {
+		t.Fatalf("got scopes %v", token.Scopes)
+	}
+
+	for _, url := range []string{URLApplyOTT, URLUnbindToken, URLTransactionList, URLTransactionDetail} {
+		if err = c.CheckCustomerScopes(token, url); err != nil {
+			t.Fatalf("%s: %v", url, err)
+		}
+	}
+
+	if err = c.CheckCustomerScopes(token, URLBalanceInquiry); err == nil {
+		t.Fatal("expected balance inquiry to require QUERY_BALANCE")
+	}
+}
@@ -0,0 +1,98 @@
+package dana
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+)
+
+type Signer interface {
+	Sign(stringToSign string) (string, error)
+}
+
+type AsymmetricSigner struct {
+	key crypto.Signer
+}
+
+func NewAsymmetricSigner(key crypto.Signer) *AsymmetricSigner {
+	return &AsymmetricSigner{key: key}
+}
+
+func (s *AsymmetricSigner) Public() crypto.PublicKey {
+	if s.key == nil {
+		return nil
+	}
+
+	return s.key.Public()
+}
+
+func (s *AsymmetricSigner) Sign(stringToSign string) (string, error) {
+	if s.key == nil {
+		return "", fmt.Errorf("private key is required for asymmetric signature")
+	}
+
+	if _, ok := s.key.Public().(*rsa.PublicKey); !ok {
+		return "", fmt.Errorf("expected *rsa.PublicKey, got %T", s.key.Public())
+	}
+
+	h := sha256.New()
+	if _, err := h.Write([]byte(stringToSign)); err != nil {
+		return "", err
+	}
+
+	signed, err := s.key.Sign(rand.Reader, h.Sum(nil), crypto.SHA256)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(signed), nil
+}
+
+type SymmetricSigner struct {
+	secret string
+}
+
+func NewSymmetricSigner(secret string) *SymmetricSigner {
+	return &SymmetricSigner{secret: secret}
+}
+
+func (s *SymmetricSigner) Sign(stringToSign string) (string, error) {
+	if len(s.secret) == 0 {
+		return "", fmt.Errorf("client secret is required for symmetric signature")
+	}
+
+	mac := hmac.New(sha512.New, []byte(s.secret))
+	if _, err := mac.Write([]byte(stringToSign)); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+func VerifySignature(publicKey *rsa.PublicKey, stringToSign, signature string) error {
+	if publicKey == nil {
+		return fmt.Errorf("public key is required to verify signature")
+	}
+
+	signed, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return err
+	}
+
+	h := sha256.Sum256([]byte(stringToSign))
+
+	return rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, h[:], signed)
+}
+
+func buildStringToSign(method, path, accessToken, bodyHash, timestamp string, symmetric bool) string {
+	if symmetric {
+		return fmt.Sprintf("%s:%s:%s:%s:%s", method, path, accessToken, bodyHash, timestamp)
+	}
+
+	return fmt.Sprintf("%s:%s:%s:%s", method, path, bodyHash, timestamp)
+}
@@ -0,0 +1,107 @@
+package dana
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const defaultSkewToleranceSeconds int64 = 300
+
+type ClockSkewConfig struct {
+	Enable           bool  `json:"enable"`
+	ToleranceSeconds int64 `json:"tolerance_seconds"`
+}
+
+func (c *Client) ClockSkew() time.Duration {
+	c.skewMutex.Lock()
+	defer c.skewMutex.Unlock()
+
+	return c.clockSkew
+}
+
+func (c *Client) SetClockSkew(skew time.Duration) {
+	c.skewMutex.Lock()
+	defer c.skewMutex.Unlock()
+
+	c.clockSkew = skew
+}
+
+func (c *Client) getSkewTolerance() time.Duration {
+	tolerance := defaultSkewToleranceSeconds
+	if c.Config.ClockSkew != nil && c.Config.ClockSkew.ToleranceSeconds > 0 {
+		tolerance = c.Config.ClockSkew.ToleranceSeconds
+	}
+
+	return time.Duration(tolerance) * time.Second
+}
+
+func (c *Client) isSkewEnabled() bool {
+	return c.Config.ClockSkew != nil && c.Config.ClockSkew.Enable
+}
+
+func (c *Client) estimateClockSkew(ctx context.Context, endpoint string, started, finished time.Time, header http.Header) {
+	if !c.isSkewEnabled() || header == nil {
+		return
+	}
+
+	date := header.Get("Date")
+	if len(date) == 0 {
+		return
+	}
+
+	serverTime, err := http.ParseTime(date)
+	if err != nil {
+		return
+	}
+
+	midpoint := started.Add(finished.Sub(started) / 2)
+	skew := serverTime.Sub(midpoint).Truncate(time.Second)
+
+	c.skewMutex.Lock()
+	if c.skewEstimated {
+		skew = (c.clockSkew + skew) / 2
+	}
+	c.clockSkew = skew
+	c.skewEstimated = true
+	c.skewMutex.Unlock()
+
+	c.getTelemetry().recordClockSkew(ctx, skew)
+
+	if absDuration(skew) > c.getSkewTolerance() {
+		c.log(ctx, slog.LevelWarn, "clock skew exceeds tolerance",
+			slog.String("endpoint", endpoint),
+			slog.Duration("skew", skew),
+			slog.Duration("tolerance", c.getSkewTolerance()),
+		)
+	}
+}
+
+func (c *Client) checkTimestamp(timestamp string) error {
+	if !c.isSkewEnabled() {
+		return nil
+	}
+
+	t, err := time.Parse(TimestampFormat, timestamp)
+	if err != nil {
+		if t, err = time.Parse(time.RFC3339, timestamp); err != nil {
+			return fmt.Errorf("invalid timestamp %s: %w", timestamp, err)
+		}
+	}
+
+	if diff := c.getCurrentTime().Sub(t); absDuration(diff) > c.getSkewTolerance() {
+		return fmt.Errorf("timestamp %s is outside the tolerance window of %s", timestamp, c.getSkewTolerance())
+	}
+
+	return nil
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+
+	return d
+}
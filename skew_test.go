@@ -0,0 +1,79 @@
+package dana
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestEstimateClockSkewFromDateHeader(t *testing.T) {
+	started := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		enable   bool
+		dates    []string
+		finished time.Duration
+		want     time.Duration
+	}{
+		{name: "server ahead", enable: true, dates: []string{started.Add(10 * time.Second).Format(http.TimeFormat)}, want: 10 * time.Second},
+		{name: "server behind", enable: true, dates: []string{started.Add(-10 * time.Second).Format(http.TimeFormat)}, want: -10 * time.Second},
+		{name: "measured against the round trip midpoint", enable: true, dates: []string{started.Add(10 * time.Second).Format(http.TimeFormat)}, finished: 4 * time.Second, want: 8 * time.Second},
+		{name: "sub second skew truncated", enable: true, dates: []string{started.Format(http.TimeFormat)}, finished: 1800 * time.Millisecond, want: 0},
+		{name: "estimates are averaged", enable: true, dates: []string{started.Add(10 * time.Second).Format(http.TimeFormat), started.Add(20 * time.Second).Format(http.TimeFormat)}, want: 15 * time.Second},
+		{name: "missing date", enable: true, dates: []string{""}, want: 0},
+		{name: "invalid date", enable: true, dates: []string{"yesterday"}, want: 0},
+		{name: "disabled", dates: []string{started.Add(10 * time.Second).Format(http.TimeFormat)}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(Config{ClockSkew: &ClockSkewConfig{Enable: tt.enable}})
+
+			for _, date := range tt.dates {
+				header := http.Header{}
+				header.Set("Date", date)
+
+				c.estimateClockSkew(context.Background(), endpointQueryPayment.name, started, started.Add(tt.finished), header)
+			}
+
+			if got := c.ClockSkew(); got != tt.want {
+				t.Fatalf("got skew %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckTimestampTolerance(t *testing.T) {
+	now := time.Date(2024, 1, 1, 7, 0, 0, 0, fallbackLocation)
+
+	tests := []struct {
+		name      string
+		config    *ClockSkewConfig
+		skew      time.Duration
+		timestamp string
+		valid     bool
+	}{
+		{name: "exactly at the default tolerance", config: &ClockSkewConfig{Enable: true}, timestamp: now.Add(-300 * time.Second).Format(TimestampFormat), valid: true},
+		{name: "one second past the default tolerance", config: &ClockSkewConfig{Enable: true}, timestamp: now.Add(-301 * time.Second).Format(TimestampFormat)},
+		{name: "one second ahead of the default tolerance", config: &ClockSkewConfig{Enable: true}, timestamp: now.Add(301 * time.Second).Format(TimestampFormat)},
+		{name: "exactly at a configured tolerance", config: &ClockSkewConfig{Enable: true, ToleranceSeconds: 10}, timestamp: now.Add(10 * time.Second).Format(TimestampFormat), valid: true},
+		{name: "past a configured tolerance", config: &ClockSkewConfig{Enable: true, ToleranceSeconds: 10}, timestamp: now.Add(11 * time.Second).Format(TimestampFormat)},
+		{name: "skew brings the timestamp inside", config: &ClockSkewConfig{Enable: true, ToleranceSeconds: 10}, skew: 60 * time.Second, timestamp: now.Add(65 * time.Second).Format(TimestampFormat), valid: true},
+		{name: "rfc3339 timestamp", config: &ClockSkewConfig{Enable: true}, timestamp: now.UTC().Format(time.RFC3339), valid: true},
+		{name: "invalid timestamp", config: &ClockSkewConfig{Enable: true}, timestamp: "now"},
+		{name: "disabled", timestamp: now.Add(-time.Hour).Format(TimestampFormat), valid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(Config{ClockSkew: tt.config}).WithClock(FixedClock{Time: now})
+			c.SetClockSkew(tt.skew)
+
+			if err := c.checkTimestamp(tt.timestamp); tt.valid != (err == nil) {
+				t.Fatalf("got %v, want valid %t", err, tt.valid)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package dana
+
+import (
+	"context"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+	"time"
+)
+
+const instrumentationName = "github.com/vannleonheart/dana-api-go"
+
+type Telemetry struct {
+	tracer         trace.Tracer
+	requests       metric.Int64Counter
+	failures       metric.Int64Counter
+	retries        metric.Int64Counter
+	tokenRefreshes metric.Int64Counter
+	duration       metric.Float64Histogram
+	clockSkew      metric.Float64Gauge
+}
+
+var noopTelemetry, _ = NewTelemetry(nil, nil)
+
+func NewTelemetry(tracerProvider trace.TracerProvider, meterProvider metric.MeterProvider) (*Telemetry, error) {
+	if tracerProvider == nil {
+		tracerProvider = tracenoop.NewTracerProvider()
+	}
+
+	if meterProvider == nil {
+		meterProvider = metricnoop.NewMeterProvider()
+	}
+
+	meter := meterProvider.Meter(instrumentationName)
+
+	requests, err := meter.Int64Counter("dana.client.requests", metric.WithDescription("Number of DANA API requests"))
+	if err != nil {
+		return nil, err
+	}
+
+	failures, err := meter.Int64Counter("dana.client.failures", metric.WithDescription("Number of failed DANA API requests"))
+	if err != nil {
+		return nil, err
+	}
+
+	retries, err := meter.Int64Counter("dana.client.retries", metric.WithDescription("Number of retried DANA API requests"))
+	if err != nil {
+		return nil, err
+	}
+
+	tokenRefreshes, err := meter.Int64Counter("dana.client.token_refreshes", metric.WithDescription("Number of B2B access token refreshes"))
+	if err != nil {
+		return nil, err
+	}
+
+	duration, err := meter.Float64Histogram("dana.client.duration", metric.WithDescription("Duration of DANA API requests"), metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+
+	clockSkew, err := meter.Float64Gauge("dana.client.clock_skew", metric.WithDescription("Estimated offset between DANA and local clock"), metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Telemetry{
+		tracer:         tracerProvider.Tracer(instrumentationName),
+		requests:       requests,
+		failures:       failures,
+		retries:        retries,
+		tokenRefreshes: tokenRefreshes,
+		duration:       duration,
+		clockSkew:      clockSkew,
+	}, nil
+}
+
+func (t *Telemetry) start(ctx context.Context, ep endpoint, externalId string) (context.Context, trace.Span) {
+	return t.tracer.Start(ctx, ep.name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("dana.endpoint", ep.path),
+			attribute.String("dana.external_id", externalId),
+		),
+	)
+}
+
+func (t *Telemetry) end(ctx context.Context, span trace.Span, ep endpoint, started time.Time, httpStatus int, responseCode string, err error) {
+	attrs := []attribute.KeyValue{
+		attribute.String("dana.endpoint", ep.path),
+		attribute.String("dana.function", ep.name),
+	}
+
+	span.SetAttributes(attribute.String("dana.response_code", responseCode))
+	if httpStatus > 0 {
+		span.SetAttributes(attribute.Int("http.response.status_code", httpStatus))
+	}
+
+	failed := err != nil || (len(responseCode) > 0 && responseCode[0] != '2')
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else if failed {
+		span.SetStatus(codes.Error, responseCode)
+	}
+
+	span.End()
+
+	t.requests.Add(ctx, 1, metric.WithAttributes(attrs...))
+	t.duration.Record(ctx, time.Since(started).Seconds(), metric.WithAttributes(attrs...))
+
+	if failed {
+		t.failures.Add(ctx, 1, metric.WithAttributes(attrs...))
+	}
+}
+
+func (t *Telemetry) recordRetry(ctx context.Context, ep endpoint) {
+	t.retries.Add(ctx, 1, metric.WithAttributes(attribute.String("dana.endpoint", ep.path), attribute.String("dana.function", ep.name)))
+}
+
+func (t *Telemetry) recordTokenRefresh(ctx context.Context) {
+	t.tokenRefreshes.Add(ctx, 1)
+}
+
+func (t *Telemetry) recordClockSkew(ctx context.Context, skew time.Duration) {
+	t.clockSkew.Record(ctx, skew.Seconds())
+}
+
+func (c *Client) getTelemetry() *Telemetry {
+	if c.telemetry != nil {
+		return c.telemetry
+	}
+
+	return noopTelemetry
+}
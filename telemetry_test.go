@@ -0,0 +1,117 @@
+package dana
+
+import (
+	"context"
+	"encoding/json"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newTelemetryClient(t *testing.T, status int, responseCode string) (*Client, *tracetest.SpanRecorder, *sdkmetric.ManualReader) {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(map[string]string{"responseCode": responseCode})
+	}))
+	t.Cleanup(srv.Close)
+
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+
+	telemetry, err := NewTelemetry(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)), sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return newTestClient(t, srv.URL).WithTelemetry(telemetry), spans, reader
+}
+
+func counterValues(t *testing.T, reader *sdkmetric.ManualReader) map[string]int64 {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+
+	values := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				for _, dp := range data.DataPoints {
+					values[m.Name] += dp.Value
+				}
+			case metricdata.Histogram[float64]:
+				for _, dp := range data.DataPoints {
+					values[m.Name] += int64(dp.Count)
+				}
+			}
+		}
+	}
+
+	return values
+}
+
+func TestTelemetryRecordsSuccessfulCall(t *testing.T) {
+	c, spans, reader := newTelemetryClient(t, http.StatusOK, "2005500")
+
+	if _, err := c.QueryPayment("ref-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	ended := spans.Ended()
+	if len(ended) != 1 || ended[0].Name() != endpointQueryPayment.name {
+		t.Fatalf("got %d spans, want one %s span", len(ended), endpointQueryPayment.name)
+	}
+
+	attrs := map[string]string{}
+	for _, attr := range ended[0].Attributes() {
+		attrs[string(attr.Key)] = attr.Value.Emit()
+	}
+
+	if attrs["dana.response_code"] != "2005500" || attrs["http.response.status_code"] != "200" || attrs["dana.endpoint"] != URLQueryPayment {
+		t.Fatalf("got attributes %v", attrs)
+	}
+
+	values := counterValues(t, reader)
+	if values["dana.client.requests"] != 1 || values["dana.client.duration"] != 1 || values["dana.client.failures"] != 0 {
+		t.Fatalf("got metrics %v", values)
+	}
+}
+
+func TestTelemetryRecordsFailedCall(t *testing.T) {
+	c, spans, reader := newTelemetryClient(t, http.StatusNotFound, "4045501")
+
+	if _, err := c.QueryPayment("ref-1"); err == nil {
+		t.Fatal("expected the call to fail")
+	}
+
+	ended := spans.Ended()
+	if len(ended) != 1 || ended[0].Status().Code != codes.Error || len(ended[0].Events()) == 0 {
+		t.Fatalf("got %d spans, want one errored span with a recorded error", len(ended))
+	}
+
+	if values := counterValues(t, reader); values["dana.client.requests"] != 1 || values["dana.client.failures"] != 1 {
+		t.Fatalf("got metrics %v", values)
+	}
+}
+
+func TestTelemetrySkipsDryRun(t *testing.T) {
+	c, spans, reader := newTelemetryClient(t, http.StatusOK, "2005500")
+
+	if _, err := c.WithDryRun(true).QueryPayment("ref-1"); !isDryRun(err) {
+		t.Fatalf("got %v, want a dry run error", err)
+	}
+
+	if len(spans.Ended()) != 0 || counterValues(t, reader)["dana.client.requests"] != 0 {
+		t.Fatal("dry run was recorded")
+	}
+}
@@ -1,24 +1,95 @@
 package dana
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"github.com/vannleonheart/goutil"
+	"strconv"
 	"strings"
 )
 
+type FlexInt int
+
 func GenerateRequestId(minLength, maxLength int, charset string) string {
-	r := goutil.NewRandomString(charset)
+	if len(charset) == 0 {
+		charset = goutil.NumCharset
+	}
+
+	if minLength < 1 {
+		minLength = 1
+	}
+
+	if maxLength < minLength {
+		maxLength = minLength
+	}
 
-	return r.WithCharset(goutil.NumCharset).GenerateRange(minLength, maxLength)
+	b := make([]byte, minLength+randomInt(maxLength-minLength+1))
+	for i := range b {
+		b[i] = charset[randomInt(len(charset))]
+	}
+
+	return string(b)
 }
 
 func EncodeRequestBody(data interface{}) string {
-	by, _ := json.Marshal(data)
+	by, _ := MarshalRequestBody(data)
+
+	return HashRequestBody(by)
+}
+
+func MarshalRequestBody(data interface{}) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+
+	if err := encoder.Encode(data); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+func MinifyAndHash(body []byte) (string, error) {
+	buf := &bytes.Buffer{}
+	if err := json.Compact(buf, body); err != nil {
+		return "", err
+	}
+
+	return HashRequestBody(buf.Bytes()), nil
+}
+
+func HashRequestBody(body []byte) string {
 	hash := sha256.New()
-	hash.Write(by)
+	hash.Write(body)
 	str := hex.EncodeToString(hash.Sum(nil))
 
 	return strings.ToLower(str)
 }
+
+func (i *FlexInt) UnmarshalJSON(data []byte) error {
+	str := strings.Trim(strings.TrimSpace(string(data)), `"`)
+	if len(str) == 0 || str == "null" {
+		*i = 0
+
+		return nil
+	}
+
+	value, err := strconv.Atoi(str)
+	if err != nil {
+		return err
+	}
+
+	*i = FlexInt(value)
+
+	return nil
+}
+
+func (r GeneralResponse) GetResponseCode() string {
+	return r.ResponseCode
+}
+
+func (r GeneralResponse) IsSuccess() bool {
+	return strings.HasPrefix(r.ResponseCode, "2")
+}
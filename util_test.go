@@ -0,0 +1,95 @@
+package dana
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMarshalRequestBodyKeepsHtmlCharacters(t *testing.T) {
+	body, err := MarshalRequestBody(map[string]interface{}{
+		"urlParams": []map[string]string{
+			{"url": "https://merchant.test/finish?order=1&status=<paid>", "type": "PAY_RETURN"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"urlParams":[{"type":"PAY_RETURN","url":"https://merchant.test/finish?order=1&status=<paid>"}]}`
+	if string(body) != want {
+		t.Fatalf("got %s, want %s", body, want)
+	}
+}
+
+func TestHashRequestBodyMatchesSentBytes(t *testing.T) {
+	key := testPrivateKey(t)
+
+	var received []byte
+	var timestamp, signature string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received, _ = io.ReadAll(r.Body)
+		timestamp = r.Header.Get("X-TIMESTAMP")
+		signature = r.Header.Get("X-SIGNATURE")
+
+		_ = json.NewEncoder(w).Encode(map[string]string{"responseCode": "2005400"})
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, srv.URL)
+
+	urlParams := []map[string]string{
+		{"url": "https://merchant.test/finish?order=1&status=paid", "type": "PAY_RETURN", "isDeeplink": "N"},
+	}
+
+	if _, err := c.DirectDebitPayment("IDR", "10000.00", "ref-1", "51051000100000000001", "Order & Co <1>", nil, nil, nil, &urlParams); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Contains(received, []byte("order=1&status=paid")) {
+		t.Fatalf("sent body escaped the url: %s", received)
+	}
+
+	stringToSign := fmt.Sprintf("POST:/%s:%s:%s", URLDirectDebitPayment, HashRequestBody(received), timestamp)
+	digest := sha256.Sum256([]byte(stringToSign))
+
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], sig); err != nil {
+		t.Fatalf("signed body hash does not match the sent body: %v", err)
+	}
+}
+
+func TestMinifyAndHashMatchesCompactBody(t *testing.T) {
+	pretty := []byte(`{
+  "merchantId": "m1",
+  "urlParams": [
+    {
+      "url": "https://merchant.test/finish?order=1&status=paid",
+      "type": "PAY_RETURN"
+    }
+  ]
+}`)
+	compact := `{"merchantId":"m1","urlParams":[{"url":"https://merchant.test/finish?order=1&status=paid","type":"PAY_RETURN"}]}`
+
+	got, err := MinifyAndHash(pretty)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := HashRequestBody([]byte(compact)); got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
@@ -1,12 +1,21 @@
 package dana
 
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
 const (
 	defaultTimezone         = "Asia/Jakarta"
-	TimestampFormat         = "2006-01-02T15:04:05+07:00"
+	TimestampFormat         = "2006-01-02T15:04:05-07:00"
 	defaultDevideId         = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36"
 	defaultChannelId        = "0"
+	customerChannelId       = "95221"
 	defaultMcc              = "412"
 	defaultExpireTime int64 = 60
+	defaultPageSize         = 20
 
 	URLAccessToken        = "v1.0/access-token/b2b.htm"
 	URLQuickPay           = "v1.0/quick-pay.htm"
@@ -22,6 +31,7 @@ const (
 	URLFinishNotify       = "v1.0/debit/notify"
 	URLRefund             = "v1.0/debit/refund.htm"
 	URLTransactionList    = "v1.0/transaction-history-list.htm"
+	URLTransactionDetail  = "v1.0/transaction-history-detail.htm"
 
 	CurrencyIDR = "IDR"
 
@@ -55,10 +65,60 @@ const (
 	VirtualAccountPanin   = "VIRTUAL_ACCOUNT_PANI"
 	VirtualAccountCIMB    = "VIRTUAL_ACCOUNT_CIMB"
 	VirtualAccountPermata = "VIRTUAL_ACCOUNT_BNLI"
+
+	BalanceTypeBalance = "BALANCE"
+
+	IdempotencyStatusPending   = "PENDING"
+	IdempotencyStatusCompleted = "COMPLETED"
+	IdempotencyStatusUnknown   = "UNKNOWN"
+
+	CircuitClosed   CircuitState = "CLOSED"
+	CircuitOpen     CircuitState = "OPEN"
+	CircuitHalfOpen CircuitState = "HALF_OPEN"
+
+	TransactionTypePayment      = "PAYMENT"
+	TransactionTypeRefund       = "REFUND"
+	TransactionTypeOfflineTopUp = "OFFLINE_TOPUP"
+	TransactionTypeTopUp        = "TOP_UP"
+	TransactionTypeRebate       = "REBATE"
+
+	TransactionStatusSuccess    = "SUCCESS"
+	TransactionStatusFailed     = "FAILED"
+	TransactionStatusInit       = "INIT"
+	TransactionStatusProcessing = "PROCESSING"
+	TransactionStatusClosed     = "CLOSED"
+	TransactionStatusRevoked    = "REVOKED"
+
+	ScopePublicId            = "PUBLIC_ID"
+	ScopeQueryBalance        = "QUERY_BALANCE"
+	ScopeMiniDana            = "MINI_DANA"
+	ScopeDefaultBasicProfile = "DEFAULT_BASIC_PROFILE"
+	ScopeAgreementPay        = "AGREEMENT_PAY"
+	ScopeCashier             = "CASHIER"
+	ScopeQueryUserInfo       = "QUERY_USER_INFO"
+	ScopeUserLoginId         = "USER_LOGIN_ID"
+	ScopeHashLoginId         = "HASH_LOGIN_ID"
+	ScopeSendOtp             = "SEND_OTP"
 )
 
+var defaultScopes = []string{ScopePublicId, ScopeQueryBalance, ScopeMiniDana}
+
+var defaultTransactionTypes = []string{TransactionTypePayment, TransactionTypeRefund, TransactionTypeOfflineTopUp, TransactionTypeTopUp, TransactionTypeRebate}
+
+var defaultTransactionStatuses = []string{TransactionStatusSuccess, TransactionStatusFailed, TransactionStatusInit, TransactionStatusProcessing, TransactionStatusClosed, TransactionStatusRevoked}
+
+var customerScopes = map[string][]string{
+	URLBalanceInquiry:    {ScopeQueryBalance},
+	URLApplyOTT:          {ScopeMiniDana},
+	URLUnbindToken:       {ScopePublicId},
+	URLTransactionList:   {ScopePublicId},
+	URLTransactionDetail: {ScopePublicId},
+}
+
 type Client struct {
 	Config              Config
+	ctx                 context.Context
+	configErr           error
 	b2bAccessToken      *AccessToken
 	customerAccessToken *AccessToken
 	origin              *string
@@ -67,36 +127,81 @@ type Client struct {
 	lon                 *string
 	requestId           *string
 	deviceId            *string
+	httpClient          *http.Client
+	logger              Logger
+	telemetry           *Telemetry
+	middlewares         []Middleware
+	idGenerator         IDGenerator
+	dryRun              bool
+	idempotencyStore    IdempotencyStore
+	clock               Clock
+	locationMutex       sync.Mutex
+	location            *time.Location
+	locationName        string
+	skewMutex           sync.Mutex
+	clockSkew           time.Duration
+	skewEstimated       bool
+	signer              Signer
+	symmetricSigner     Signer
+	circuitMutex        sync.Mutex
+	circuits            map[string]*circuit
+	circuitHook         CircuitStateHook
+	rateLimitMutex      sync.Mutex
+	rateLimiter         *rateLimiter
+	keyMutex            sync.Mutex
+	parsedKey           string
+	parsedSigner        Signer
+	logMutex            sync.Mutex
+	fileLogger          *FileLogger
+	redactMutex         sync.Mutex
+	redactor            *Redactor
+	redactFields        []string
+	allowFields         []string
 }
 
 type Config struct {
-	ApiUrl               string     `json:"api_url"`
-	WebUrl               string     `json:"web_url"`
-	MerchantId           string     `json:"merchant_id"`
-	ClientId             string     `json:"client_id"`
-	ClientSecret         string     `json:"client_secret"`
-	PublicKey            string     `json:"public_key"`
-	PrivateKey           string     `json:"private_key"`
-	FinishPaymentUrl     string     `json:"finish_payment_url"`
-	FinishRefundUrl      string     `json:"finish_refund_url"`
-	FinishPaymentCodeUrl string     `json:"finish_payment_code_url"`
-	FinishRedirectUrl    string     `json:"finish_redirect_url"`
-	Timezone             string     `json:"timezone"`
-	DefaultExpireTime    *int64     `json:"default_expire_time"`
-	Origin               string     `json:"origin"`
-	IpAddress            string     `json:"ip_address"`
-	Latitude             string     `json:"latitude"`
-	Longitude            string     `json:"longitude"`
-	Log                  *LogConfig `json:"log,omitempty"`
+	Environment          string                `json:"environment"`
+	ApiUrl               string                `json:"api_url"`
+	WebUrl               string                `json:"web_url"`
+	MerchantId           string                `json:"merchant_id"`
+	ClientId             string                `json:"client_id"`
+	ClientSecret         string                `json:"client_secret"`
+	PublicKey            string                `json:"public_key"`
+	NextPublicKey        string                `json:"next_public_key"`
+	PrivateKey           string                `json:"private_key"`
+	PrivateKeyFile       string                `json:"private_key_file"`
+	PublicKeyFile        string                `json:"public_key_file"`
+	Mcc                  string                `json:"mcc"`
+	FinishPaymentUrl     string                `json:"finish_payment_url"`
+	FinishRefundUrl      string                `json:"finish_refund_url"`
+	FinishPaymentCodeUrl string                `json:"finish_payment_code_url"`
+	FinishRedirectUrl    string                `json:"finish_redirect_url"`
+	Timezone             string                `json:"timezone"`
+	DefaultExpireTime    *int64                `json:"default_expire_time"`
+	Origin               string                `json:"origin"`
+	IpAddress            string                `json:"ip_address"`
+	Latitude             string                `json:"latitude"`
+	Longitude            string                `json:"longitude"`
+	DisableDefaultScopes bool                  `json:"disable_default_scopes"`
+	Scopes               []string              `json:"scopes"`
+	ChannelId            string                `json:"channel_id"`
+	ChannelIds           map[string]string     `json:"channel_ids"`
+	Retry                *RetryConfig          `json:"retry,omitempty"`
+	ClockSkew            *ClockSkewConfig      `json:"clock_skew,omitempty"`
+	RateLimit            *RateLimitConfig      `json:"rate_limit,omitempty"`
+	CircuitBreaker       *CircuitBreakerConfig `json:"circuit_breaker,omitempty"`
+	Log                  *LogConfig            `json:"log,omitempty"`
 }
 
 type LogConfig struct {
-	Enable    bool   `json:"enable"`
-	Level     string `json:"level"`
-	Path      string `json:"path"`
-	Filename  string `json:"filename"`
-	Extension string `json:"extension"`
-	Rotation  string `json:"rotation"`
+	Enable       bool     `json:"enable"`
+	Level        string   `json:"level"`
+	Path         string   `json:"path"`
+	Filename     string   `json:"filename"`
+	Extension    string   `json:"extension"`
+	Rotation     string   `json:"rotation"`
+	RedactFields []string `json:"redact_fields"`
+	AllowFields  []string `json:"allow_fields"`
 }
 
 type GeneralResponse struct {
@@ -110,10 +215,11 @@ type GetB2BAccessTokenResponse struct {
 }
 
 type AccessToken struct {
-	AccessToken           string  `json:"accessToken"`
-	TokenType             string  `json:"tokenType"`
-	ExpiresIn             *int    `json:"expiresIn"`
-	AccessTokenExpiryTime *string `json:"accessTokenExpiryTime"`
+	AccessToken           string   `json:"accessToken"`
+	TokenType             string   `json:"tokenType"`
+	ExpiresIn             *int     `json:"expiresIn"`
+	AccessTokenExpiryTime *string  `json:"accessTokenExpiryTime"`
+	Scopes                []string `json:"scopes,omitempty"`
 }
 
 type QuickPayResponse struct {
@@ -190,6 +296,28 @@ type CustomerApplyOTTResponse struct {
 	Value        string `json:"value"`
 }
 
+type BalanceInquiryResponse struct {
+	GeneralResponse
+	ReferenceNo        *string                 `json:"referenceNo,omitempty"`
+	PartnerReferenceNo *string                 `json:"partnerReferenceNo,omitempty"`
+	AccountNo          *string                 `json:"accountNo,omitempty"`
+	Name               *string                 `json:"name,omitempty"`
+	AccountInfos       []AccountInfo           `json:"accountInfos"`
+	AdditionalInfo     *map[string]interface{} `json:"additionalInfo,omitempty"`
+}
+
+type AccountInfo struct {
+	BalanceType              string  `json:"balanceType"`
+	Amount                   *Money  `json:"amount,omitempty"`
+	FloatAmount              *Money  `json:"floatAmount,omitempty"`
+	HoldAmount               *Money  `json:"holdAmount,omitempty"`
+	AvailableBalance         *Money  `json:"availableBalance,omitempty"`
+	LedgerBalance            *Money  `json:"ledgerBalance,omitempty"`
+	CurrentMultilateralLimit *Money  `json:"currentMultilateralLimit,omitempty"`
+	RegistrationStatusCode   *string `json:"registrationStatusCode,omitempty"`
+	Status                   *string `json:"status,omitempty"`
+}
+
 type CancelOrderRequest struct {
 	GeneralResponse
 	OriginalReferenceNo        string `json:"originalReferenceNo"`
@@ -207,31 +335,70 @@ type RefundOrderResponse struct {
 	RefundAmount               Money  `json:"refundAmount"`
 }
 
+type TransactionHistoryRequest struct {
+	FromDateTime *string   `json:"fromDateTime,omitempty"`
+	ToDateTime   *string   `json:"toDateTime,omitempty"`
+	PageSize     *int      `json:"pageSize,omitempty"`
+	PageNumber   *int      `json:"pageNumber,omitempty"`
+	Types        *[]string `json:"types,omitempty"`
+	Statuses     *[]string `json:"statuses,omitempty"`
+}
+
 type TransactionHistoryResponse struct {
 	GeneralResponse
-	DetailData []struct {
-		ReferenceNo        string `json:"referenceNo"`
-		PartnerReferenceNo string `json:"partnerReferenceNo"`
-		DateTime           string `json:"dateTime"`
-		Amount             Money  `json:"amount"`
-		Type               string `json:"type"`
-		Status             string `json:"status"`
-		SourceOfFunds      []struct {
-			Source string `json:"source"`
-			Amount Money  `json:"amount"`
-		}
-		AdditionalInfo map[string]interface{} `json:"additionalInfo"`
-	} `json:"detailData"`
+	DetailData     []TransactionHistoryItem `json:"detailData"`
 	AdditionalInfo *struct {
-		Paginator struct {
-			PageNum    string `json:"pageNum"`
-			PageSize   string `json:"pageSize"`
-			TotalCount string `json:"totalCount"`
-			TotalPage  string `json:"totalPage"`
-		}
+		Paginator *Paginator `json:"paginator"`
 	} `json:"additionalInfo"`
 }
 
+type TransactionHistoryItem struct {
+	ReferenceNo        string                 `json:"referenceNo"`
+	PartnerReferenceNo string                 `json:"partnerReferenceNo"`
+	DateTime           string                 `json:"dateTime"`
+	Amount             Money                  `json:"amount"`
+	Type               string                 `json:"type"`
+	Status             string                 `json:"status"`
+	SourceOfFunds      []SourceOfFund         `json:"sourceOfFunds"`
+	AdditionalInfo     map[string]interface{} `json:"additionalInfo"`
+}
+
+type TransactionHistoryDetailResponse struct {
+	GeneralResponse
+	ReferenceNo        string                 `json:"referenceNo"`
+	PartnerReferenceNo string                 `json:"partnerReferenceNo"`
+	DateTime           string                 `json:"dateTime"`
+	Amount             Money                  `json:"amount"`
+	FeeAmount          *Money                 `json:"feeAmount,omitempty"`
+	Type               string                 `json:"type"`
+	Status             string                 `json:"status"`
+	Remark             *string                `json:"remark,omitempty"`
+	SourceOfFunds      []SourceOfFund         `json:"sourceOfFunds"`
+	MerchantInfo       *MerchantInfo          `json:"merchantInfo,omitempty"`
+	AdditionalInfo     map[string]interface{} `json:"additionalInfo"`
+}
+
+type MerchantInfo struct {
+	MerchantId    string  `json:"merchantId"`
+	MerchantName  *string `json:"merchantName,omitempty"`
+	SubMerchantId *string `json:"subMerchantId,omitempty"`
+	ShopId        *string `json:"shopId,omitempty"`
+	ShopName      *string `json:"shopName,omitempty"`
+	Mcc           *string `json:"mcc,omitempty"`
+}
+
+type SourceOfFund struct {
+	Source string `json:"source"`
+	Amount Money  `json:"amount"`
+}
+
+type Paginator struct {
+	PageNum    FlexInt `json:"pageNum"`
+	PageSize   FlexInt `json:"pageSize"`
+	TotalCount FlexInt `json:"totalCount"`
+	TotalPage  FlexInt `json:"totalPage"`
+}
+
 type Money struct {
 	Currency      string  `json:"currency"`
 	Value         string  `json:"value"`
@@ -0,0 +1,89 @@
+package dana
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+var ErrPublicKeyRequired = errors.New("public_key is not set, copy DANA's public key from the merchant dashboard into Config.PublicKey to verify notifications")
+
+func (c *Client) VerifyNotification(method, path string, body []byte, timestamp, signature string) error {
+	if err := c.checkTimestamp(timestamp); err != nil {
+		return err
+	}
+
+	bodyHash, err := MinifyAndHash(body)
+	if err != nil {
+		return err
+	}
+
+	strToSign := buildStringToSign(strings.ToUpper(method), path, "", bodyHash, timestamp, false)
+
+	var errs []error
+
+	for _, key := range []string{c.Config.PublicKey, c.Config.NextPublicKey} {
+		if len(strings.TrimSpace(key)) == 0 {
+			continue
+		}
+
+		publicKey, err := ParsePublicKey([]byte(key))
+		if err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+
+		if err = VerifySignature(publicKey, strToSign, signature); err == nil {
+			return nil
+		}
+
+		errs = append(errs, err)
+	}
+
+	if len(errs) == 0 {
+		return ErrPublicKeyRequired
+	}
+
+	return fmt.Errorf("invalid notification signature: %w", errors.Join(errs...))
+}
+
+func (c *Client) ParseNotification(r *http.Request) (*NotificationRequest, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = c.VerifyNotification(r.Method, r.URL.Path, body, r.Header.Get("X-TIMESTAMP"), r.Header.Get("X-SIGNATURE")); err != nil {
+		c.log(r.Context(), slog.LevelError, "error when verify notification",
+			slog.String("endpoint", "ParseNotification"),
+			slog.String("path", r.URL.Path),
+			errorAttr(err),
+		)
+
+		return nil, err
+	}
+
+	var notification NotificationRequest
+
+	if err = json.Unmarshal(body, &notification); err != nil {
+		return nil, err
+	}
+
+	c.log(r.Context(), slog.LevelInfo, "notification received",
+		slog.String("endpoint", "ParseNotification"),
+		slog.String("path", r.URL.Path),
+		slog.String("referenceNo", notification.OriginalPartnerReferenceNo),
+		slog.String("status", notification.LatestTransactionStatus),
+	)
+	c.log(r.Context(), slog.LevelDebug, "notification payload",
+		slog.String("endpoint", "ParseNotification"),
+		slog.Any("notification", notification),
+	)
+
+	return &notification, nil
+}
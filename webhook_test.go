@@ -0,0 +1,63 @@
+package dana
+
+import (
+	"errors"
+	"testing"
+)
+
+func signNotification(t *testing.T, c *Client, body []byte) (string, string) {
+	t.Helper()
+
+	bodyHash, err := MinifyAndHash(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	timestamp := c.getTimestamp()
+
+	signature, err := NewAsymmetricSigner(testNextPrivateKey(t)).Sign(buildStringToSign("POST", "/"+URLFinishNotify, "", bodyHash, timestamp, false))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return timestamp, signature
+}
+
+func TestVerifyNotificationKeyRotation(t *testing.T) {
+	current := testPublicKey(t, testPrivateKey(t))
+	next := testPublicKey(t, testNextPrivateKey(t))
+	body := []byte(`{"originalPartnerReferenceNo": "ref-1", "latestTransactionStatus": "00"}`)
+
+	tests := []struct {
+		name          string
+		publicKey     string
+		nextPublicKey string
+		valid         bool
+	}{
+		{name: "signed by the primary key", publicKey: next, valid: true},
+		{name: "signed by the next key during rotation", publicKey: current, nextPublicKey: next, valid: true},
+		{name: "next key alone", nextPublicKey: next, valid: true},
+		{name: "signed by neither key", publicKey: current},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(Config{PublicKey: tt.publicKey, NextPublicKey: tt.nextPublicKey})
+			timestamp, signature := signNotification(t, c, body)
+
+			err := c.VerifyNotification("POST", "/"+URLFinishNotify, body, timestamp, signature)
+			if tt.valid != (err == nil) {
+				t.Fatalf("got %v, want valid %t", err, tt.valid)
+			}
+		})
+	}
+}
+
+func TestVerifyNotificationWithoutPublicKey(t *testing.T) {
+	c := New(Config{Environment: EnvironmentSandbox})
+
+	err := c.VerifyNotification("POST", "/v1.0/debit/notify", []byte(`{}`), "2024-01-01T00:00:00+07:00", "c2lnbmF0dXJl")
+	if !errors.Is(err, ErrPublicKeyRequired) {
+		t.Fatalf("got %v, want ErrPublicKeyRequired", err)
+	}
+}